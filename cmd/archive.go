@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/OmSingh2003/git-util/pkg/gitops"
+	"github.com/spf13/cobra"
+)
+
+// Variables to hold the flag values for the archive command
+var (
+	archiveScan   scanFlags
+	archiveOutDir string
+)
+
+// archiveCmd represents the archive command
+var archiveCmd = &cobra.Command{
+	Use:   "archive",
+	Short: "Bundle multiple Git repositories into an output directory for backup.",
+	Long: `Scans a directory for Git repositories and runs 'git bundle create --all'
+for each one, writing <name>.bundle files into the --out directory. The name is
+the repository's path relative to the scan root, with path separators replaced
+by "__".
+
+A bundle can be restored with 'git clone <file>.bundle'.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if archiveOutDir == "" {
+			return fmt.Errorf("--out is required")
+		}
+
+		// --- Determine Target and Output Directories ---
+		targetDir, err := resolveTargetDir(archiveScan.directory)
+		if err != nil {
+			return err
+		}
+		outDir, err := expandPath(archiveOutDir)
+		if err != nil {
+			return err
+		}
+		outDir, err = filepath.Abs(outDir)
+		if err != nil {
+			return fmt.Errorf("failed to get absolute path for output directory: %w", err)
+		}
+		if err := os.MkdirAll(outDir, 0o755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+
+		fmt.Printf("Scanning directory: %s (Output: %s)\n", targetDir, outDir)
+
+		// --- Find Repositories ---
+		repos, err := archiveScan.findRepos(targetDir)
+		if err != nil {
+			return err
+		}
+
+		if len(repos) == 0 {
+			fmt.Println("No Git repositories found in the specified directory.")
+			return nil
+		}
+
+		fmt.Printf("\n--- Archiving Repositories ---\n")
+
+		// --- Calculate Max Path Length for Formatting ---
+		maxLen := maxDisplayLen(targetDir, repos)
+
+		// --- Process Each Repository ---
+		successCount := 0
+		failCount := 0
+		skipCount := 0
+		var totalSize int64
+		for _, repoPath := range repos {
+			relPath := displayPath(targetDir, repoPath)
+			bundleName := strings.ReplaceAll(filepath.ToSlash(relPath), "/", "__") + ".bundle"
+			bundlePath := filepath.Join(outDir, bundleName)
+
+			fmt.Printf("%-*s : ", maxLen, relPath)
+			_, err := gitops.RunGitCommand("-C", repoPath, "bundle", "create", bundlePath, "--all")
+			if err != nil {
+				if strings.Contains(err.Error(), "Refusing to create empty bundle") {
+					fmt.Println("[Skipped: no commits]")
+					skipCount++
+					continue
+				}
+				fmt.Println("FAILED")
+				fmt.Fprintf(os.Stderr, "  Error for %s: %v\n", relPath, err)
+				failCount++
+				continue
+			}
+
+			info, err := os.Stat(bundlePath)
+			if err != nil {
+				fmt.Println("FAILED")
+				fmt.Fprintf(os.Stderr, "  Error for %s: bundle not written: %v\n", relPath, err)
+				failCount++
+				continue
+			}
+			totalSize += info.Size()
+			fmt.Printf("OK (%s) -> %s\n", formatBytes(info.Size()), bundleName)
+			successCount++
+		}
+
+		// Print summary
+		fmt.Printf("\n--- Summary ---\n")
+		fmt.Printf("  Archived:  %d (%s total)\n", successCount, formatBytes(totalSize))
+		fmt.Printf("  Failed:    %d\n", failCount)
+		if skipCount > 0 {
+			fmt.Printf("  Skipped:   %d\n", skipCount)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(archiveCmd)
+	addScanFlags(archiveCmd, &archiveScan)
+	archiveCmd.Flags().StringVarP(&archiveOutDir, "out", "o", "", "Directory to write the .bundle files to (created if missing)")
+}
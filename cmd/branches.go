@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/OmSingh2003/git-util/pkg/gitops"
+	"github.com/spf13/cobra"
+)
+
+// Variables to hold the flag values for the branches command
+var (
+	branchesScan     scanFlags
+	branchesMerged   bool
+	branchesNoMerged bool
+	branchesFormat   string
+)
+
+// branchEntry is one local branch in the inventory.
+type branchEntry struct {
+	Path         string `json:"path"`
+	RelativePath string `json:"relativePath"`
+	Branch       string `json:"branch"`
+	Upstream     string `json:"upstream,omitempty"`
+	Current      bool   `json:"current"`
+	MainBranch   string `json:"mainBranch,omitempty"` // the default branch merged status is relative to
+	Merged       bool   `json:"merged"`
+}
+
+// branchesSummary counts the inventory.
+type branchesSummary struct {
+	Repos    int `json:"repos"`
+	Branches int `json:"branches"`
+	Merged   int `json:"merged"`
+	Errors   int `json:"errors"`
+}
+
+// branchesReport is the JSON document printed by 'branches --format json'.
+type branchesReport struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	Branches      []branchEntry   `json:"branches"`
+	Summary       branchesSummary `json:"summary"`
+}
+
+// branchesCmd represents the branches command
+var branchesCmd = &cobra.Command{
+	Use:   "branches",
+	Short: "List the local branches of multiple Git repositories.",
+	Long: `Scans a directory for Git repositories and lists every local branch, grouped
+per repository, with its upstream and whether it is merged into the
+repository's default branch (detected as for the branch cleaner, or taken from
+the 'main' configuration setting).
+
+--merged and --no-merged limit the list to branches that are, or are not,
+merged into the default branch. --format json prints a flat list of branches.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if branchesMerged && branchesNoMerged {
+			return fmt.Errorf("--merged and --no-merged are mutually exclusive")
+		}
+		format := strings.ToLower(branchesFormat)
+		if format != "text" && format != "json" {
+			return fmt.Errorf("invalid format '%s': must be 'text' or 'json'", branchesFormat)
+		}
+		if err := loadGlobalConfig(); err != nil {
+			return err
+		}
+
+		// --- Determine Target Directory ---
+		targetDir, err := resolveTargetDir(branchesScan.directory)
+		if err != nil {
+			return err
+		}
+
+		if format == "text" {
+			fmt.Printf("Scanning directory: %s\n", targetDir)
+		}
+
+		// --- Find Repositories ---
+		repos, err := branchesScan.findRepos(targetDir)
+		if err != nil {
+			return err
+		}
+
+		if len(repos) == 0 && format == "text" {
+			fmt.Println("No Git repositories found in the specified directory.")
+			return nil
+		}
+
+		// --- Collect Branches Per Repository ---
+		report := branchesReport{SchemaVersion: jsonSchemaVersion, Branches: []branchEntry{}}
+		for _, repoPath := range repos {
+			relPath := displayPath(targetDir, repoPath)
+			entries, mainBranch, err := repoBranches(repoPath)
+			report.Summary.Repos++
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to list branches for %s: %v\n", relPath, err)
+				report.Summary.Errors++
+				if format == "text" {
+					fmt.Printf("\n=== %s ===\n  [Error]\n", relPath)
+				}
+				continue
+			}
+
+			var kept []branchEntry
+			for _, e := range entries {
+				if (branchesMerged && !e.Merged) || (branchesNoMerged && e.Merged) {
+					continue
+				}
+				e.RelativePath = relPath
+				kept = append(kept, e)
+				report.Summary.Branches++
+				if e.Merged {
+					report.Summary.Merged++
+				}
+			}
+			report.Branches = append(report.Branches, kept...)
+
+			if format == "text" {
+				printRepoBranches(relPath, mainBranch, kept)
+			}
+		}
+
+		if format == "json" {
+			return writeJSON(os.Stdout, report)
+		}
+
+		// Print summary
+		fmt.Printf("\n--- Summary ---\n")
+		fmt.Printf("  Repositories: %d\n", report.Summary.Repos)
+		fmt.Printf("  Branches:     %d (%d merged)\n", report.Summary.Branches, report.Summary.Merged)
+		if report.Summary.Errors > 0 {
+			fmt.Printf("  Errors:       %d\n", report.Summary.Errors)
+		}
+		return nil
+	},
+}
+
+// repoBranches lists the local branches of the repository at repoPath with their
+// merged status relative to its default branch, which is also returned. If no
+// default branch can be determined, every branch is reported as not merged.
+func repoBranches(repoPath string) ([]branchEntry, string, error) {
+	branches, err := gitops.LocalBranches(repoPath)
+	if err != nil {
+		return nil, "", err
+	}
+	current, _ := gitops.CurrentBranch(repoPath)
+
+	cfg, err := repoConfig(repoPath)
+	if err != nil {
+		return nil, "", err
+	}
+	mainBranch := cfg.Main
+	if mainBranch == "" {
+		mainBranch, _ = gitops.DetectDefaultMainBranchIn(repoPath)
+	}
+	merged := make(map[string]bool)
+	if mainBranch != "" {
+		names, err := gitops.MergedBranches(repoPath, mainBranch)
+		if err != nil {
+			return nil, "", err
+		}
+		for _, name := range names {
+			merged[name] = true
+		}
+	}
+
+	entries := make([]branchEntry, 0, len(branches))
+	for _, b := range branches {
+		entries = append(entries, branchEntry{
+			Path:       repoPath,
+			Branch:     b.Name,
+			Upstream:   b.Upstream,
+			Current:    b.Name == current,
+			MainBranch: mainBranch,
+			// The default branch is trivially merged into itself; it is not a cleanup candidate.
+			Merged: merged[b.Name] && b.Name != mainBranch,
+		})
+	}
+	return entries, mainBranch, nil
+}
+
+// printRepoBranches prints the branch list of one repository as an aligned table.
+func printRepoBranches(relPath, mainBranch string, entries []branchEntry) {
+	if mainBranch != "" {
+		fmt.Printf("\n=== %s (main: %s) ===\n", relPath, mainBranch)
+	} else {
+		fmt.Printf("\n=== %s (main: unknown) ===\n", relPath)
+	}
+	if len(entries) == 0 {
+		fmt.Println("  (no matching branches)")
+		return
+	}
+	t := &table{}
+	for _, e := range entries {
+		marker := " "
+		if e.Current {
+			marker = "*"
+		}
+		upstream := e.Upstream
+		if upstream == "" {
+			upstream = "-"
+		}
+		merged := ""
+		if e.Merged {
+			merged = "[merged]"
+		}
+		t.addRow(marker, e.Branch, upstream, merged)
+	}
+	t.write(os.Stdout, "  ")
+}
+
+func init() {
+	rootCmd.AddCommand(branchesCmd)
+	addScanFlags(branchesCmd, &branchesScan)
+	branchesCmd.Flags().BoolVar(&branchesMerged, "merged", false, "Only list branches merged into the repository's default branch")
+	branchesCmd.Flags().BoolVar(&branchesNoMerged, "no-merged", false, "Only list branches not merged into the repository's default branch")
+	branchesCmd.Flags().StringVarP(&branchesFormat, "format", "f", "text", "Output format: 'text' or 'json'")
+}
@@ -0,0 +1,645 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OmSingh2003/git-util/internal/output"
+	"github.com/OmSingh2003/git-util/pkg/forge"
+	"github.com/OmSingh2003/git-util/pkg/gitops"
+)
+
+// protectedPatterns holds the branch names and glob patterns read from --protected-file.
+var protectedPatterns []string
+
+// cleanRepoResult is the outcome of running the branch cleaner in one repository.
+type cleanRepoResult struct {
+	candidates int
+	deleted    int // includes branches that would be deleted in a dry run
+	failed     int
+	gone       int   // already deleted, e.g. by another process, when the cleaner got to them
+	err        error // set when the repository could not be processed at all
+
+	// mainBranch describes the branch(es) candidates were checked against, and
+	// mainSource where they came from: "--main", "--assume-main", "config" or "detected".
+	mainBranch string
+	mainSource string
+}
+
+// cleanTotals aggregates cleaner results across repositories. It is safe for
+// concurrent use by the multi-repo worker pool.
+type cleanTotals struct {
+	mu         sync.Mutex
+	repos      int
+	errored    int
+	candidates int
+	deleted    int
+	failed     int
+	gone       int
+	mainCounts map[string]int // main branch (as in cleanRepoResult.mainBranch) -> repositories
+}
+
+// add records the result of one repository.
+func (t *cleanTotals) add(r cleanRepoResult) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.repos++
+	if r.mainBranch != "" {
+		if t.mainCounts == nil {
+			t.mainCounts = make(map[string]int)
+		}
+		t.mainCounts[r.mainBranch]++
+	}
+	if r.err != nil {
+		t.errored++
+		return
+	}
+	t.candidates += r.candidates
+	t.deleted += r.deleted
+	t.failed += r.failed
+	t.gone += r.gone
+}
+
+// cleanRepo runs the branch cleaner in the repository at repoPath ("" for the
+// current directory), writing its report to w. Deletions within a repository
+// are always performed one after another.
+func cleanRepo(w io.Writer, repoPath string) cleanRepoResult {
+	var result cleanRepoResult
+
+	cfg, err := repoConfig(repoPath)
+	if err != nil {
+		result.err = err
+		return result
+	}
+
+	// --- Step 1: Determine the target main branch(es) ---
+	// The -m flag wins over the configured main branch, which wins over detection.
+	// Detection runs per repository, so each one is cleaned against its own default.
+	// --assume-main bypasses all of it.
+	targets := mainBranchNames
+	result.mainSource = "--main"
+	if assumeMain != "" {
+		targets, result.mainSource = []string{assumeMain}, "--assume-main"
+	}
+	if len(targets) == 0 && cfg.Main != "" {
+		targets, result.mainSource = []string{cfg.Main}, "config"
+	}
+	if len(targets) == 0 {
+		// Call helper from gitops package
+		detected, err := gitops.DetectDefaultMainBranchIn(repoPath)
+		if err != nil {
+			result.err = fmt.Errorf("could not detect default main branch: %w", err)
+			return result
+		}
+		targets, result.mainSource = []string{detected}, "detected"
+	}
+	targetMainBranch := mergeTargetLabel(targets)
+	result.mainBranch = targetMainBranch
+
+	// --- Steps 2-4: List and filter the branches merged into the target(s) ---
+	branchesToProcess, err := mergedIntoTargets(repoPath, targets)
+	if err != nil {
+		result.err = err
+		return result
+	}
+
+	// --- Step 4b: Drop protected branches ---
+	branchesToProcess, protected, err := filterProtectedBranches(repoPath, branchesToProcess)
+	if err != nil {
+		result.err = err
+		return result
+	}
+	if len(protected) > 0 {
+		fmt.Fprintf(w, "Skipping protected branches: %s\n", strings.Join(protected, ", "))
+	}
+	if len(cfg.Exclude) > 0 {
+		var excluded []string
+		branchesToProcess, excluded = filterExcludedBranches(branchesToProcess, cfg.Exclude)
+		if len(excluded) > 0 {
+			fmt.Fprintf(w, "Skipping excluded branches: %s\n", strings.Join(excluded, ", "))
+		}
+	}
+
+	// --- Step 4c: Keep only branches by the requested author ---
+	if authorPattern != "" {
+		var others int
+		branchesToProcess, others, err = filterBranchesByAuthor(repoPath, branchesToProcess, authorPattern)
+		if err != nil {
+			result.err = err
+			return result
+		}
+		if others > 0 {
+			fmt.Fprintf(w, "Ignoring %d merged branches not authored by '%s'.\n", others, authorPattern)
+		}
+	}
+	result.candidates = len(branchesToProcess)
+
+	// --- Step 5: Perform action ---
+	if len(branchesToProcess) == 0 {
+		fmt.Fprintf(w, "No local branches found that are merged into %s (excluding the current branch).\n", targetMainBranch)
+		return result
+	}
+
+	if interactive {
+		branchesToProcess, err = selectBranches(w, os.Stdin, branchesToProcess)
+		if err != nil {
+			result.err = err
+			return result
+		}
+		if len(branchesToProcess) == 0 {
+			fmt.Fprintln(w, "No branches selected; nothing deleted.")
+			return result
+		}
+	} else if !deleteBranches {
+		fmt.Fprintf(w, "The following local branches are merged into %s and can potentially be deleted:\n", targetMainBranch)
+		for _, branch := range branchesToProcess {
+			if showAge {
+				fmt.Fprintf(w, "  - %s (last commit: %s)\n", branch, branchAgeText(repoPath, branch))
+				continue
+			}
+			fmt.Fprintf(w, "  - %s\n", branch)
+		}
+		fmt.Fprintln(w, "\nRun with --delete flag (or -d) to remove them.")
+		return result
+	}
+
+	fmt.Fprintf(w, "Processing deletion for branches merged into %s...\n", targetMainBranch)
+	if batchDelete && !dryRun {
+		deleteBranchesBatch(w, repoPath, branchesToProcess, &result)
+		branchesToProcess = nil // all handled by the batch
+	}
+	for _, branch := range branchesToProcess {
+		if dryRun {
+			fmt.Fprintf(w, "[Dry Run] Would attempt to delete branch: %s\n", branch)
+			result.deleted++
+		} else {
+			fmt.Fprintf(w, "Attempting to delete branch: %s...", branch)
+			// Call helper from gitops package
+			// The output ("Deleted branch X (was <sha>).") is kept so 'undo-delete' can restore the branch.
+			output, err := gitops.RunGitCommand("-C", repoPath, "branch", "-d", branch)
+			if isBranchNotFound(err, branch) {
+				fmt.Fprintln(w, " Already gone.")
+				result.gone++
+			} else if err != nil {
+				fmt.Fprintf(w, " Failed (%v)\n", err) // Error from RunGitCommand includes stderr
+				result.failed++
+			} else {
+				fmt.Fprintln(w, " Deleted.")
+				result.deleted++
+				recordDeletedBranch(w, repoPath, branch, output)
+			}
+		}
+	}
+	fmt.Fprintf(w, "\nSummary:\n")
+	if dryRun {
+		fmt.Fprintf(w, "  Dry run complete. %d branches would have been targeted for deletion.\n", result.deleted)
+	} else {
+		fmt.Fprintf(w, "  Successfully deleted: %d\n", result.deleted)
+		fmt.Fprintf(w, "  Failed to delete:   %d\n", result.failed)
+		if result.gone > 0 {
+			fmt.Fprintf(w, "  Already gone:       %d\n", result.gone)
+		}
+		if result.failed > 0 {
+			fmt.Fprintln(w, "  (Failures might occur if a branch has unmerged changes specific to it; use 'git branch -D' manually if needed.)")
+		}
+	}
+	return result
+}
+
+// branchAgeText returns the age of branch's last commit for --show-age, or
+// "unknown" if it cannot be read.
+func branchAgeText(repoPath, branch string) string {
+	age, err := gitops.BranchAge(repoPath, branch)
+	if err != nil || age == "" {
+		return "unknown"
+	}
+	return age
+}
+
+// mergeTargetLabel describes the branches a candidate must be merged into, as
+// used in the cleaner's messages: "main", or e.g. "all of main, develop".
+func mergeTargetLabel(targets []string) string {
+	if len(targets) == 1 {
+		return targets[0]
+	}
+	if mergedIntoAny {
+		return "any of " + strings.Join(targets, ", ")
+	}
+	return "all of " + strings.Join(targets, ", ")
+}
+
+// mergedIntoTargets returns the local branches of the repository at repoPath
+// that are merged into every one of targets (with --merged-into-any: into at
+// least one), in the order 'git branch --merged' lists them. The current
+// branch and the targets themselves are never included. A target may be a
+// remote-tracking branch such as origin/main, to compare against the remote
+// rather than a possibly stale local branch; its local counterpart (main) is
+// then not offered for deletion either.
+func mergedIntoTargets(repoPath string, targets []string) ([]string, error) {
+	remotes, _ := gitops.Remotes(repoPath)
+	isTarget := make(map[string]bool, len(targets))
+	for _, target := range targets {
+		remote, branch, qualified := splitRemoteRef(target, remotes)
+		if err := verifyMergeTarget(repoPath, target, remote, qualified); err != nil {
+			return nil, err
+		}
+		isTarget[target] = true
+		if qualified {
+			isTarget[branch] = true
+		}
+	}
+
+	var order []string
+	count := make(map[string]int) // branch -> number of targets it is merged into
+	for _, target := range targets {
+		// --- Step 2: Run `git branch --merged <target>` ---
+		// Call helper from gitops package
+		mergedBranchesOutput, err := gitops.RunGitCommand("-C", repoPath, "branch", "--merged", target)
+		if err != nil {
+			if strings.Contains(err.Error(), "warn: no such ref") || strings.Contains(err.Error(), "error: malformed object name") {
+				return nil, fmt.Errorf("specified main branch '%s' not found", target)
+			}
+			return nil, fmt.Errorf("failed to list merged branches: %w", err)
+		}
+
+		// --- Step 3: Parse the output ---
+		for _, line := range strings.Split(mergedBranchesOutput, "\n") {
+			branchName := strings.TrimSpace(line)
+			if branchName == "" || strings.HasPrefix(branchName, "* ") {
+				continue
+			}
+			if count[branchName] == 0 {
+				order = append(order, branchName)
+			}
+			count[branchName]++
+		}
+	}
+
+	// --- Step 4: Filter the branches ---
+	var branches []string
+	for _, branchName := range order {
+		if isTarget[branchName] {
+			continue
+		}
+		if mergedIntoAny || count[branchName] == len(targets) {
+			branches = append(branches, branchName)
+		}
+	}
+	return branches, nil
+}
+
+// verifyMergeTarget checks that target names a commit in the repository at
+// repoPath. --assume-main skips the check; a missing branch then surfaces as
+// git's own error when it is used.
+func verifyMergeTarget(repoPath, target, remote string, qualified bool) error {
+	if assumeMain != "" {
+		return nil
+	}
+	if _, err := gitops.RunGitCommand("-C", repoPath, "rev-parse", "--verify", "--quiet", target+"^{commit}"); err != nil {
+		if qualified {
+			return fmt.Errorf("remote-tracking branch '%s' not found (run 'git fetch %s', or check the name)", target, remote)
+		}
+		return fmt.Errorf("specified main branch '%s' not found", target)
+	}
+	return nil
+}
+
+// splitRemoteRef splits a remote-qualified ref such as "origin/main" into the
+// remote and branch name if its first element is one of remotes.
+func splitRemoteRef(ref string, remotes []string) (remote, branch string, ok bool) {
+	for _, r := range remotes {
+		if b, found := strings.CutPrefix(ref, r+"/"); found && b != "" {
+			return r, b, true
+		}
+	}
+	return "", "", false
+}
+
+// deleteBranchesBatch deletes branches with a single 'git branch -d' call and
+// reports each one as the per-branch loop would. git deletes what it can and
+// fails for the rest; those are retried one by one so that each failure is
+// reported with its own error message.
+func deleteBranchesBatch(w io.Writer, repoPath string, branches []string, result *cleanRepoResult) {
+	args := append([]string{"-C", repoPath, "branch", "-d"}, branches...)
+	output, batchErr := gitops.RunGitCommand(args...)
+
+	deleted := make(map[string]string) // branch -> its "Deleted branch" line
+	for _, line := range strings.Split(output, "\n") {
+		if name, _, ok := gitops.ParseDeletedBranch(line); ok {
+			deleted[name] = line
+		}
+	}
+
+	for _, branch := range branches {
+		fmt.Fprintf(w, "Attempting to delete branch: %s...", branch)
+		if line, ok := deleted[branch]; ok {
+			fmt.Fprintln(w, " Deleted.")
+			result.deleted++
+			recordDeletedBranch(w, repoPath, branch, line)
+			continue
+		}
+		if batchErr == nil {
+			// git reported success without a line for this branch; check rather than guess.
+			if !gitops.RefExists(repoPath, "refs/heads/"+branch) {
+				fmt.Fprintln(w, " Deleted.")
+				result.deleted++
+				continue
+			}
+		}
+		retryOutput, err := gitops.RunGitCommand("-C", repoPath, "branch", "-d", branch)
+		if isBranchNotFound(err, branch) {
+			fmt.Fprintln(w, " Already gone.")
+			result.gone++
+			continue
+		}
+		if err != nil {
+			fmt.Fprintf(w, " Failed (%v)\n", err)
+			result.failed++
+			continue
+		}
+		fmt.Fprintln(w, " Deleted.")
+		result.deleted++
+		recordDeletedBranch(w, repoPath, branch, retryOutput)
+	}
+}
+
+// isBranchNotFound reports whether err is 'git branch -d' failing because the
+// branch no longer exists, e.g. because another process deleted it after the
+// cleaner listed it. Rerunning the cleaner is then harmless rather than a failure.
+func isBranchNotFound(err error, branch string) bool {
+	return err != nil && strings.Contains(err.Error(), fmt.Sprintf("branch '%s' not found", branch))
+}
+
+// recordDeletedBranch saves the SHA a deleted branch pointed to, taken from the
+// output of 'git branch -d', so that 'git-util undo-delete' can recreate it.
+func recordDeletedBranch(w io.Writer, repoPath, branch, output string) {
+	name, sha, ok := gitops.ParseDeletedBranch(output)
+	if !ok || name != branch {
+		fmt.Fprintf(w, "  Warning: could not determine the last commit of %s; it cannot be restored with undo-delete.\n", branch)
+		return
+	}
+	// The commit is still present, so expand the abbreviated SHA while it is unambiguous.
+	if full, err := gitops.RunGitCommand("-C", repoPath, "rev-parse", "--verify", "--quiet", sha+"^{commit}"); err == nil {
+		sha = full
+	}
+	record := gitops.DeletedBranch{Name: branch, SHA: sha, DeletedAt: time.Now()}
+	if err := gitops.RecordDeletedBranch(repoPath, record); err != nil {
+		fmt.Fprintf(w, "  Warning: failed to record deleted branch %s: %v\n", branch, err)
+	}
+}
+
+// loadCleanerFilters reads the files referenced by the cleaner's filter flags.
+// It runs once per invocation, before any repository is processed.
+func loadCleanerFilters() error {
+	if err := loadGlobalConfig(); err != nil {
+		return err
+	}
+	protectedPatterns = nil
+	if protectedFile == "" {
+		return nil
+	}
+	f, err := os.Open(protectedFile)
+	if err != nil {
+		return fmt.Errorf("failed to read protected branches file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if _, err := path.Match(line, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q in %s: %w", line, protectedFile, err)
+		}
+		protectedPatterns = append(protectedPatterns, line)
+	}
+	return scanner.Err()
+}
+
+// filterProtectedBranches splits branches into those that may be deleted and those
+// that are protected, either by --protected-file or (with --protected-from-remote)
+// by branch protection on origin. If the remote cannot be queried the repository
+// is not cleaned at all rather than risking a protected branch.
+func filterProtectedBranches(repoPath string, branches []string) (kept, protected []string, err error) {
+	remoteProtected := make(map[string]bool)
+	if protectedFromRemote {
+		originURL, err := gitops.RemoteURL(repoPath, "origin")
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not read origin URL for --protected-from-remote: %w", err)
+		}
+		remote, err := forge.ParseRemoteURL(originURL)
+		if err != nil {
+			return nil, nil, err
+		}
+		token := githubToken
+		if token == "" {
+			token = os.Getenv("GITHUB_TOKEN")
+		}
+		names, err := forge.GitHubProtectedBranches(interruptContext(), remote, token)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not read branch protection for %s/%s: %w", remote.Owner, remote.Repo, err)
+		}
+		for _, name := range names {
+			remoteProtected[name] = true
+		}
+	}
+
+	for _, branch := range branches {
+		if remoteProtected[branch] || matchesAnyPattern(protectedPatterns, branch) {
+			protected = append(protected, branch)
+		} else {
+			kept = append(kept, branch)
+		}
+	}
+	return kept, protected, nil
+}
+
+// filterExcludedBranches splits branches into those that may be deleted and
+// those matching one of the configured exclude patterns.
+func filterExcludedBranches(branches, patterns []string) (kept, excluded []string) {
+	for _, branch := range branches {
+		if matchesAnyPattern(patterns, branch) {
+			excluded = append(excluded, branch)
+		} else {
+			kept = append(kept, branch)
+		}
+	}
+	return kept, excluded
+}
+
+// filterBranchesByAuthor keeps the branches whose tip commit author matches pattern
+// and returns how many were dropped.
+func filterBranchesByAuthor(repoPath string, branches []string, pattern string) (kept []string, dropped int, err error) {
+	for _, branch := range branches {
+		name, email, err := gitops.TipAuthor(repoPath, "refs/heads/"+branch)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to read author of branch '%s': %w", branch, err)
+		}
+		if matchesAuthor(pattern, name) || matchesAuthor(pattern, email) {
+			kept = append(kept, branch)
+		} else {
+			dropped++
+		}
+	}
+	return kept, dropped, nil
+}
+
+// matchesAuthor matches value against an --author pattern: a full glob match if
+// the pattern contains glob characters, otherwise a case-insensitive substring.
+func matchesAuthor(pattern, value string) bool {
+	if strings.ContainsAny(pattern, "*?[") {
+		ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(value))
+		return ok
+	}
+	return strings.Contains(strings.ToLower(value), strings.ToLower(pattern))
+}
+
+// matchesAnyPattern reports whether name matches one of the glob patterns.
+func matchesAnyPattern(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// runMultiRepoCleaner runs the branch cleaner in every repository under --directory.
+// Repositories are processed by a pool of --jobs workers; each repository's report
+// is buffered and printed in discovery order once it is complete.
+func runMultiRepoCleaner() error {
+	if err := validateJobs(cleanJobs); err != nil {
+		return err
+	}
+
+	// --- Determine Target Directory ---
+	targetDir, err := resolveTargetDir(cleanScan.directory)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Scanning directory: %s\n", targetDir)
+
+	// --- Find Repositories ---
+	repos, err := cleanScan.findRepos(targetDir)
+	if err != nil {
+		return err
+	}
+
+	if len(repos) == 0 {
+		fmt.Println("No Git repositories found in the specified directory.")
+		return nil
+	}
+
+	// --- Clean Repositories in Parallel, Printing Reports in Discovery Order ---
+	out := output.NewOrdered(os.Stdout, os.Stderr, len(repos))
+	totals := &cleanTotals{}
+	runParallel(cleanJobs, len(repos), func(i int) {
+		task := out.Task(i)
+		// The report is buffered so that the header can name the main branch used.
+		var report strings.Builder
+		result := cleanRepo(&report, repos[i])
+		if result.mainBranch != "" {
+			task.Printf("\n=== %s (main: %s, %s) ===\n", displayPath(targetDir, repos[i]), result.mainBranch, result.mainSource)
+		} else {
+			task.Printf("\n=== %s ===\n", displayPath(targetDir, repos[i]))
+		}
+		io.WriteString(task.Stdout(), report.String())
+		if result.err != nil {
+			task.Printf("Error: %v\n", result.err)
+		}
+		totals.add(result)
+		task.Done()
+	})
+
+	// --- Overall Summary ---
+	fmt.Printf("\n--- Overall Summary ---\n")
+	fmt.Printf("  Repositories processed: %d\n", totals.repos)
+	if totals.errored > 0 {
+		fmt.Printf("  Repositories with errors: %d\n", totals.errored)
+	}
+	if len(totals.mainCounts) > 1 {
+		fmt.Printf("  Main branches:          %s\n", mainBranchCounts(totals.mainCounts))
+	}
+	fmt.Printf("  Merged branches found:  %d\n", totals.candidates)
+	if deleteBranches {
+		if dryRun {
+			fmt.Printf("  Would be deleted:       %d\n", totals.deleted)
+		} else {
+			fmt.Printf("  Successfully deleted:   %d\n", totals.deleted)
+			fmt.Printf("  Failed to delete:       %d\n", totals.failed)
+			if totals.gone > 0 {
+				fmt.Printf("  Already gone:           %d\n", totals.gone)
+			}
+		}
+	}
+	return nil
+}
+
+// mainBranchCounts lists how many repositories were cleaned against each main
+// branch, most common first, e.g. "main (12), master (3)".
+func mainBranchCounts(counts map[string]int) string {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s (%d)", name, counts[name])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// runCleanerCount implements --count: it prints only the number of branches the
+// cleaner would offer for deletion, summed over every repository with
+// --directory. Repositories that cannot be processed are reported on stderr and
+// left out of the count.
+func runCleanerCount() error {
+	if cleanScan.directory == "" {
+		result := cleanRepo(io.Discard, "")
+		if result.err != nil {
+			return result.err
+		}
+		fmt.Println(result.candidates)
+		return nil
+	}
+	if err := validateJobs(cleanJobs); err != nil {
+		return err
+	}
+	targetDir, err := resolveTargetDir(cleanScan.directory)
+	if err != nil {
+		return err
+	}
+	repos, err := cleanScan.findRepos(targetDir)
+	if err != nil {
+		return err
+	}
+	out := output.NewOrdered(io.Discard, os.Stderr, len(repos))
+	totals := &cleanTotals{}
+	runParallel(cleanJobs, len(repos), func(i int) {
+		task := out.Task(i)
+		result := cleanRepo(io.Discard, repos[i])
+		if result.err != nil {
+			task.Errorf("Warning: skipping %s: %v\n", displayPath(targetDir, repos[i]), result.err)
+		}
+		totals.add(result)
+		task.Done()
+	})
+	fmt.Println(totals.candidates)
+	return nil
+}
@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/OmSingh2003/git-util/pkg/gitops"
+	"github.com/spf13/cobra"
+)
+
+// Variables to hold the flag values for the clone command
+var (
+	cloneDirectory string
+	cloneFile      string
+	cloneJobs      int
+	cloneDepth     int
+)
+
+// cloneCmd represents the clone command
+var cloneCmd = &cobra.Command{
+	Use:   "clone [url...]",
+	Short: "Clone many Git repositories into a directory in parallel.",
+	Long: `Clones every given repository URL into --directory (default: the current
+directory), each into a folder named after the repository, e.g.
+git@github.com:me/api.git is cloned into ./api. URLs can also be listed in a
+file with --file, one per line ('#' starts a comment). Repositories whose
+folder already exists are skipped. URLs that would be cloned into the same
+folder (e.g. github.com/a/api and github.com/b/api) are reported as conflicts
+before cloning starts: only the first of them is cloned.
+
+Up to --jobs clones run at the same time. On a terminal a progress line shows
+how many repositories are complete and which are being cloned; otherwise one
+line is printed per repository as it finishes. Ctrl-C stops starting new
+clones and waits for the running ones to be interrupted.
+
+--depth N makes shallow, single-branch clones with only the last N commits of
+the default branch, which is much faster for large repositories. Each result
+says whether the clone is shallow; git ignores --depth for plain local paths
+(use a file:// URL), and such clones are reported as full.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if cloneJobs < 1 {
+			return fmt.Errorf("invalid --jobs value %d: must be at least 1", cloneJobs)
+		}
+		if cloneDepth < 0 {
+			return fmt.Errorf("invalid --depth value %d: must be 0 (full clone) or more", cloneDepth)
+		}
+
+		// --- Collect URLs ---
+		urls := append([]string(nil), args...)
+		if cloneFile != "" {
+			fromFile, err := readCloneList(cloneFile)
+			if err != nil {
+				return err
+			}
+			urls = append(urls, fromFile...)
+		}
+		if len(urls) == 0 {
+			return fmt.Errorf("nothing to clone: pass repository URLs or --file")
+		}
+
+		// --- Determine Target Directory ---
+		targetDir, err := resolveTargetDir(cloneDirectory)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Cloning %d repositories into %s (Jobs: %d)\n\n", len(urls), targetDir, cloneJobs)
+
+		ctx := interruptContext()
+
+		// --- Detect Conflicting Destinations ---
+		maxLen := 0
+		for _, url := range urls {
+			maxLen = max(maxLen, len(cloneDirName(url)))
+		}
+		// Each folder goes to the first URL naming it; cloning the others too
+		// would skip or fail them at random, depending on which finished first.
+		claimedBy := make(map[string]string)
+		var queue []string
+		conflicts := 0
+		for _, url := range urls {
+			name := cloneDirName(url)
+			if first, ok := claimedBy[name]; ok {
+				if url == first {
+					fmt.Printf("%-*s : [Conflict: %s is listed twice]\n", maxLen, name, url)
+				} else {
+					fmt.Printf("%-*s : [Conflict: folder already used by %s, not cloning %s]\n", maxLen, name, first, url)
+				}
+				conflicts++
+				continue
+			}
+			claimedBy[name] = url
+			queue = append(queue, url)
+		}
+		if conflicts > 0 {
+			fmt.Println()
+		}
+
+		// --- Clone Repositories in Parallel ---
+		prog := newProgress(os.Stdout, len(queue))
+		var mu sync.Mutex
+		var cloned, skipped, failed, cancelled int
+
+		work := make(chan string)
+		var wg sync.WaitGroup
+		for n := 0; n < cloneJobs && n < len(queue); n++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for url := range work {
+					name := cloneDirName(url)
+					prog.start(name)
+					status, err := cloneRepo(ctx, url, filepath.Join(targetDir, name))
+					prog.finish(name, fmt.Sprintf("%-*s : %s", maxLen, name, status))
+					if err != nil && status == "FAILED" {
+						prog.printErr(fmt.Sprintf("  Error for %s: %v", name, err))
+					}
+
+					mu.Lock()
+					switch {
+					case strings.HasPrefix(status, "OK"):
+						cloned++
+					case status == "FAILED":
+						failed++
+					case status == "[Cancelled]":
+						cancelled++
+					default:
+						skipped++
+					}
+					mu.Unlock()
+				}
+			}()
+		}
+	feed:
+		for _, url := range queue {
+			select {
+			case work <- url:
+			case <-ctx.Done():
+				break feed
+			}
+		}
+		close(work)
+		wg.Wait()
+		prog.stop()
+
+		notStarted := len(queue) - cloned - skipped - failed - cancelled
+
+		// Print summary
+		fmt.Printf("\n--- Summary ---\n")
+		fmt.Printf("  Cloned:  %d\n", cloned)
+		fmt.Printf("  Skipped: %d\n", skipped)
+		fmt.Printf("  Failed:  %d\n", failed)
+		if conflicts > 0 {
+			fmt.Printf("  Conflicts: %d (same folder as an earlier URL, not cloned)\n", conflicts)
+		}
+		if ctx.Err() != nil {
+			fmt.Printf("  Cancelled: %d (interrupted: %d, not started: %d)\n", cancelled+notStarted, cancelled, notStarted)
+			cmd.SilenceUsage = true
+			return errors.New("clone interrupted")
+		}
+		return nil
+	},
+}
+
+// cloneRepo clones url into dest unless dest already exists, returning the
+// result text printed for it.
+func cloneRepo(ctx context.Context, url, dest string) (string, error) {
+	if ctx.Err() != nil {
+		return "[Cancelled]", ctx.Err()
+	}
+	if _, err := os.Stat(dest); err == nil {
+		return "[Skipped: already exists]", nil
+	}
+	// Ctrl-C reaches the git child processes too, as they share our process group.
+	gitArgs := []string{"clone", "--quiet"}
+	if cloneDepth > 0 {
+		gitArgs = append(gitArgs, "--depth", strconv.Itoa(cloneDepth), "--single-branch")
+	}
+	if _, err := gitops.RunGitCommand(append(gitArgs, url, dest)...); err != nil {
+		if ctx.Err() != nil {
+			return "[Cancelled]", err
+		}
+		return "FAILED", err
+	}
+	if cloneDepth > 0 {
+		if shallow, err := gitops.RunGitCommand("-C", dest, "rev-parse", "--is-shallow-repository"); err == nil && shallow == "true" {
+			return fmt.Sprintf("OK (shallow, depth %d)", cloneDepth), nil
+		}
+		return "OK (full history: --depth was ignored)", nil
+	}
+	return "OK", nil
+}
+
+// cloneDirName returns the folder a repository URL is cloned into, as 'git clone' would pick it.
+func cloneDirName(url string) string {
+	name := strings.TrimRight(url, "/")
+	if i := strings.LastIndexAny(name, "/:"); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.TrimSuffix(name, ".git")
+}
+
+// readCloneList reads the repository URLs listed in path, skipping blank lines and comments.
+func readCloneList(path string) ([]string, error) {
+	listPath, err := expandPath(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(listPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read clone list: %w", err)
+	}
+	defer f.Close()
+
+	var urls []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		urls = append(urls, line)
+	}
+	return urls, scanner.Err()
+}
+
+func init() {
+	rootCmd.AddCommand(cloneCmd)
+	cloneCmd.Flags().StringVarP(&cloneDirectory, "directory", "D", "", "Directory to clone into (defaults to current directory)")
+	cloneCmd.Flags().StringVar(&cloneFile, "file", "", "File listing repository URLs to clone, one per line")
+	cloneCmd.Flags().IntVarP(&cloneJobs, "jobs", "j", 4, "Number of repositories to clone in parallel")
+	cloneCmd.Flags().IntVar(&cloneDepth, "depth", 0, "Make shallow, single-branch clones with only the last N commits")
+}
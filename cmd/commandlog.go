@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/OmSingh2003/git-util/pkg/gitops"
+)
+
+// commandLog is the open --command-log file and the runner writing to it.
+var commandLog struct {
+	file   *os.File
+	runner *gitops.LoggingRunner
+}
+
+// openCommandLog starts recording every git invocation to --command-log, if given.
+func openCommandLog() error {
+	if commandLogPath == "" {
+		return nil
+	}
+	logPath, err := expandPath(commandLogPath)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open command log: %w", err)
+	}
+	commandLog.file = f
+	commandLog.runner = gitops.NewLoggingRunner(gitops.DefaultRunner, f)
+	gitops.SetRunner(commandLog.runner)
+	return nil
+}
+
+// closeCommandLog closes the --command-log file, reporting entries that could not be written.
+func closeCommandLog() {
+	if commandLog.file == nil {
+		return
+	}
+	if err := commandLog.runner.Err(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: command log %s is incomplete: %v\n", commandLogPath, err)
+	}
+	if err := commandLog.file.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to close command log: %v\n", err)
+	}
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/OmSingh2003/git-util/pkg/gitops"
+	"github.com/spf13/cobra"
+)
+
+// Variables to hold the flag values for the commit command
+var (
+	commitScan     scanFlags
+	commitMessage  string
+	commitTemplate string
+	commitYes      bool
+)
+
+// commitCmd represents the commit command
+var commitCmd = &cobra.Command{
+	Use:   "commit",
+	Short: "Stage and commit all changes with the same message across multiple Git repositories.",
+	Long: `Scans a directory for Git repositories and, in every repository with local
+changes, runs 'git add -A' followed by 'git commit' with the same message.
+Clean repositories are skipped.
+
+The message is given with -m, or read from a file with --template (useful for
+multi-line messages). Since this writes history, the affected repositories are
+listed first and nothing is committed unless --yes is given.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// --- Determine Commit Message ---
+		if (commitMessage == "") == (commitTemplate == "") {
+			return fmt.Errorf("specify exactly one of --message (-m) or --template")
+		}
+		message := commitMessage
+		if commitTemplate != "" {
+			templatePath, err := expandPath(commitTemplate)
+			if err != nil {
+				return err
+			}
+			data, err := os.ReadFile(templatePath)
+			if err != nil {
+				return fmt.Errorf("failed to read commit template: %w", err)
+			}
+			message = string(data)
+		}
+		if strings.TrimSpace(message) == "" {
+			return fmt.Errorf("commit message is empty")
+		}
+
+		// --- Determine Target Directory ---
+		targetDir, err := resolveTargetDir(commitScan.directory)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Scanning directory: %s\n", targetDir)
+
+		// --- Find Repositories ---
+		repos, err := commitScan.findRepos(targetDir)
+		if err != nil {
+			return err
+		}
+
+		// --- Keep Only Dirty Repositories ---
+		var dirtyRepos []string
+		for _, repoPath := range repos {
+			dirty, err := gitops.IsDirty(repoPath)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to get status for %s: %v\n", displayPath(targetDir, repoPath), err)
+				continue
+			}
+			if dirty {
+				dirtyRepos = append(dirtyRepos, repoPath)
+			}
+		}
+
+		if len(dirtyRepos) == 0 {
+			fmt.Println("No repositories with local changes found.")
+			return nil
+		}
+
+		subject, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+		fmt.Printf("\nThe following repositories have local changes and will be committed (%q):\n", subject)
+		for _, repoPath := range dirtyRepos {
+			fmt.Printf("  - %s\n", displayPath(targetDir, repoPath))
+		}
+
+		if !commitYes {
+			fmt.Println("\nRun with --yes (or -y) to commit these changes.")
+			return nil
+		}
+
+		fmt.Printf("\n--- Committing Changes ---\n")
+
+		// --- Process Each Repository ---
+		maxLen := maxDisplayLen(targetDir, dirtyRepos)
+		successCount := 0
+		failCount := 0
+		for _, repoPath := range dirtyRepos {
+			relPath := displayPath(targetDir, repoPath)
+
+			sha, err := commitAll(repoPath, message)
+			if err != nil {
+				fmt.Printf("%-*s : FAILED\n", maxLen, relPath)
+				fmt.Fprintf(os.Stderr, "  Error for %s: %v\n", relPath, err)
+				failCount++
+				continue
+			}
+			fmt.Printf("%-*s : committed %s\n", maxLen, relPath, sha)
+			successCount++
+		}
+
+		// Print summary
+		fmt.Printf("\n--- Summary ---\n")
+		fmt.Printf("  Committed: %d\n", successCount)
+		fmt.Printf("  Failed:    %d\n", failCount)
+
+		if failCount > 0 {
+			cmd.SilenceUsage = true // the failures are already reported above
+			return fmt.Errorf("%d of %d repositories failed to commit", failCount, len(dirtyRepos))
+		}
+		return nil
+	},
+}
+
+// commitAll stages every change in the repository at repoPath, commits it with
+// message and returns the abbreviated SHA of the new commit.
+func commitAll(repoPath, message string) (string, error) {
+	if _, err := gitops.RunGitCommand("-C", repoPath, "add", "-A"); err != nil {
+		return "", err
+	}
+	if _, err := gitops.RunGitCommand("-C", repoPath, "commit", "--quiet", "-m", message); err != nil {
+		return "", err
+	}
+	return gitops.RunGitCommand("-C", repoPath, "rev-parse", "--short", "HEAD")
+}
+
+func init() {
+	rootCmd.AddCommand(commitCmd)
+	addScanFlags(commitCmd, &commitScan)
+	commitCmd.Flags().StringVarP(&commitMessage, "message", "m", "", "Commit message used in every repository")
+	commitCmd.Flags().StringVar(&commitTemplate, "template", "", "Read the commit message from this file")
+	commitCmd.Flags().BoolVarP(&commitYes, "yes", "y", false, "Confirm committing in every listed repository")
+}
@@ -0,0 +1,443 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/OmSingh2003/git-util/pkg/gitops"
+	"github.com/spf13/cobra"
+)
+
+// --- Shared Helpers for Multi-Repo Commands ---
+
+// resolveTargetDir turns the value of a --directory flag into an absolute path.
+// An empty value means the current working directory. A leading '~' and
+// environment variables ($HOME, ${WORK}) are expanded, since no shell does it for us.
+// The result must be an existing directory, so that a mistyped path is reported
+// as such instead of as a scan that found no repositories.
+func resolveTargetDir(dir string) (string, error) {
+	targetDir := dir
+	if targetDir == "" {
+		var err error
+		targetDir, err = os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("failed to get current directory: %w", err)
+		}
+	}
+	targetDir, err := expandPath(targetDir)
+	if err != nil {
+		return "", err
+	}
+	targetDir, err = filepath.Abs(targetDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path for target directory: %w", err)
+	}
+	info, err := os.Stat(targetDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("directory %s does not exist", targetDir)
+		}
+		return "", fmt.Errorf("cannot access directory %s: %w", targetDir, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", targetDir)
+	}
+	return targetDir, nil
+}
+
+// requireRepo checks up front that dir ("" for the current directory) is inside
+// a Git repository, so that single-repository commands fail with a clear error
+// instead of a confusing one from their first git call. Bare repositories and
+// .git directories count as inside.
+func requireRepo(dir string) error {
+	args := []string{"rev-parse", "--is-inside-work-tree"}
+	if dir != "" {
+		args = append([]string{"-C", dir}, args...)
+	}
+	if _, err := gitops.RunGitCommand(args...); err != nil {
+		where, wdErr := resolveTargetDir(dir)
+		if wdErr != nil {
+			where = dir
+		}
+		return fmt.Errorf("not inside a git repository: %s", where)
+	}
+	return nil
+}
+
+// expandPath expands environment variables and a leading "~" or "~/" in p.
+func expandPath(p string) (string, error) {
+	p = os.ExpandEnv(p)
+	if p == "~" || strings.HasPrefix(p, "~/") || strings.HasPrefix(p, "~"+string(filepath.Separator)) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("cannot expand '~': %w", err)
+		}
+		p = filepath.Join(home, p[1:])
+	}
+	return p, nil
+}
+
+// displayPath returns the path of repoPath relative to targetDir for printing.
+// The scan root itself is shown by its base name instead of ".".
+func displayPath(targetDir, repoPath string) string {
+	relPath, _ := filepath.Rel(targetDir, repoPath)
+	if relPath == "." {
+		relPath = filepath.Base(targetDir)
+	}
+	return relPath
+}
+
+// maxDisplayLen returns the length of the longest display path, used to align output.
+func maxDisplayLen(targetDir string, repos []string) int {
+	maxLen := 0
+	for _, repoPath := range repos {
+		if l := len(displayPath(targetDir, repoPath)); l > maxLen {
+			maxLen = l
+		}
+	}
+	return maxLen
+}
+
+// scanFlags holds the repository-discovery flags shared by the multi-repo commands.
+type scanFlags struct {
+	directory    string
+	noSubmodules bool
+	stdin        bool
+	dirNameOnly  bool
+	ignoreErrors bool
+	excludeDirs  []string
+	maxRepos     int
+
+	// reposFile caches the discovered repositories between runs; refresh rescans anyway.
+	reposFile string
+	refresh   bool
+
+	// scanJobs is the number of directories read in parallel during discovery.
+	scanJobs int
+}
+
+// defaultMaxRepos is the default --max-repos: far more than a workspace holds,
+// but low enough to stop an accidental scan of a whole filesystem early.
+const defaultMaxRepos = 5000
+
+// lastScanErrors holds the paths the most recent scan could not read. They are
+// reported after the command's own output by printScanErrors.
+var lastScanErrors []gitops.WalkError
+
+// addScanFlags registers the repository-discovery flags on cmd, storing values in f.
+func addScanFlags(cmd *cobra.Command, f *scanFlags) {
+	cmd.Flags().StringVarP(&f.directory, "directory", "D", "", "Directory to scan for Git repositories (defaults to current directory)")
+	cmd.Flags().BoolVar(&f.noSubmodules, "no-submodules", true, "Exclude repositories that are submodules of another discovered repository")
+	cmd.Flags().StringArrayVar(&f.excludeDirs, "exclude-dir", nil, "Skip this directory during discovery: a path (relative to the scan root or absolute) or a glob against the absolute path; repeatable")
+	cmd.Flags().IntVar(&f.maxRepos, "max-repos", defaultMaxRepos, "Stop scanning after this many repositories have been found; 0 for no limit")
+	cmd.Flags().BoolVar(&f.ignoreErrors, "ignore-errors", true, "Continue when directories cannot be read, listing them at the end; set to false to fail instead")
+	cmd.Flags().IntVar(&f.scanJobs, "scan-jobs", 1, "Read this many directories in parallel while discovering repositories (faster on network filesystems); 1 walks serially")
+	cmd.Flags().StringVar(&f.reposFile, "repos-file", "", "Cache the discovered repository paths in this file and reuse them on later runs instead of scanning")
+	cmd.Flags().BoolVar(&f.refresh, "refresh", false, "With --repos-file, rescan the directory and rewrite the cache")
+}
+
+// addStdinFlag registers --stdin on cmd, which replaces discovery with a list of
+// repository paths read from standard input.
+func addStdinFlag(cmd *cobra.Command, f *scanFlags) {
+	cmd.Flags().BoolVar(&f.stdin, "stdin", false, "Read repository paths from stdin (one per line) instead of scanning --directory")
+}
+
+// addDirNameOnlyFlag registers --dir-name-only on cmd, which shortens display
+// paths to the repository directory name.
+func addDirNameOnlyFlag(cmd *cobra.Command, f *scanFlags) {
+	cmd.Flags().BoolVar(&f.dirNameOnly, "dir-name-only", false, "Show only each repository's directory name, adding parent directories when names clash")
+}
+
+// displayNames maps each repository to the name printed for it: the path
+// relative to targetDir, or with --dir-name-only just the directory name.
+// Clashing names (two repositories called "api") get as many parent
+// directories prepended as needed to tell them apart, e.g. "svc/api" and "web/api".
+func (f *scanFlags) displayNames(targetDir string, repos []string) map[string]string {
+	names := make(map[string]string, len(repos))
+	if !f.dirNameOnly {
+		for _, repoPath := range repos {
+			names[repoPath] = displayPath(targetDir, repoPath)
+		}
+		return names
+	}
+
+	pending := repos
+	for depth := 1; len(pending) > 0; depth++ {
+		byName := make(map[string][]string)
+		for _, repoPath := range pending {
+			name := lastPathElements(displayPath(targetDir, repoPath), depth)
+			byName[name] = append(byName[name], repoPath)
+		}
+		pending = nil
+		for name, clashing := range byName {
+			if len(clashing) == 1 {
+				names[clashing[0]] = name
+				continue
+			}
+			for _, repoPath := range clashing {
+				// A path with no more parents to add keeps its full form.
+				if relPath := displayPath(targetDir, repoPath); relPath == name {
+					names[repoPath] = relPath
+				} else {
+					pending = append(pending, repoPath)
+				}
+			}
+		}
+	}
+	return names
+}
+
+// lastPathElements returns the last n elements of the slash- or
+// separator-delimited path p, or p itself if it has fewer.
+func lastPathElements(p string, n int) string {
+	parts := strings.Split(filepath.ToSlash(p), "/")
+	if n < len(parts) {
+		parts = parts[len(parts)-n:]
+	}
+	return filepath.Join(parts...)
+}
+
+// maxNameLen returns the length of the longest value in names, used to align output.
+func maxNameLen(names map[string]string) int {
+	maxLen := 0
+	for _, name := range names {
+		maxLen = max(maxLen, len(name))
+	}
+	return maxLen
+}
+
+// findRepos discovers the repositories under targetDir according to f. With
+// --stdin the paths are read from standard input instead, and targetDir is only
+// used to shorten them for display.
+// The same repository reached through different paths (e.g. a symlink) is
+// only returned once.
+func (f *scanFlags) findRepos(targetDir string) ([]string, error) {
+	var repos []string
+	var err error
+	switch {
+	case f.stdin:
+		repos, err = readRepoList(os.Stdin)
+	case f.reposFile != "":
+		repos, err = f.findReposCached(targetDir)
+	default:
+		repos, err = f.scanRepos(targetDir)
+	}
+	if err != nil {
+		return nil, err
+	}
+	repos, collapsed := dedupeRepos(repos)
+	if collapsed > 0 {
+		verbosef("Collapsed %d repository paths that resolve to an already listed repository.\n", collapsed)
+	}
+	return repos, nil
+}
+
+// dedupeRepos drops repositories whose canonical path (symlinks resolved) was
+// already seen, keeping the first path each repository was found at, and
+// returns how many were dropped.
+func dedupeRepos(repos []string) ([]string, int) {
+	seen := make(map[string]bool, len(repos))
+	kept := make([]string, 0, len(repos))
+	for _, repoPath := range repos {
+		canonical, err := filepath.EvalSymlinks(repoPath)
+		if err != nil {
+			canonical = repoPath // checked again when the repository is used
+		}
+		if seen[canonical] {
+			continue
+		}
+		seen[canonical] = true
+		kept = append(kept, repoPath)
+	}
+	return kept, len(repos) - len(kept)
+}
+
+// verbosef prints a diagnostic to stderr with the global --verbose flag,
+// masking credentials in any URL it mentions.
+func verbosef(format string, args ...any) {
+	if verbose {
+		fmt.Fprint(os.Stderr, gitops.RedactURL(fmt.Sprintf(format, args...)))
+	}
+}
+
+// scanRepos walks targetDir for repositories according to f.
+func (f *scanFlags) scanRepos(targetDir string) ([]string, error) {
+	if f.maxRepos < 0 {
+		return nil, fmt.Errorf("invalid --max-repos value %d: must be 0 (no limit) or more", f.maxRepos)
+	}
+	if f.scanJobs < 1 {
+		return nil, fmt.Errorf("invalid --scan-jobs value %d: must be at least 1", f.scanJobs)
+	}
+	var excludeDirs []string
+	for _, dir := range f.excludeDirs {
+		dir, err := expandPath(dir)
+		if err != nil {
+			return nil, err
+		}
+		if !filepath.IsAbs(dir) {
+			// Relative values are taken relative to the scan root.
+			dir = filepath.Join(targetDir, dir)
+		}
+		excludeDirs = append(excludeDirs, dir)
+	}
+	result, err := gitops.FindGitReposWithOptions(targetDir, gitops.FindOptions{
+		ExcludeSubmodules: f.noSubmodules,
+		ExcludeDirs:       excludeDirs,
+		MaxRepos:          f.maxRepos,
+		Parallel:          f.scanJobs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error finding repositories: %w", err)
+	}
+	if result.ExcludedDirs > 0 {
+		fmt.Fprintf(os.Stderr, "Skipped %d directories matching --exclude-dir.\n", result.ExcludedDirs)
+	}
+	if result.LimitReached {
+		fmt.Fprintf(os.Stderr, "Warning: stopped scanning after %d repositories (--max-repos); the results are incomplete. Point --directory at a narrower path, or raise --max-repos.\n", f.maxRepos)
+	}
+	lastScanErrors = result.Errors
+	if len(result.Errors) > 0 && !f.ignoreErrors {
+		for _, walkErr := range result.Errors {
+			fmt.Fprintf(os.Stderr, "  %s: %v\n", walkErr.Path, walkErr.Err)
+		}
+		lastScanErrors = nil
+		return nil, fmt.Errorf("%d paths under %s could not be read (--ignore-errors=false)", len(result.Errors), targetDir)
+	}
+	return result.Repos, nil
+}
+
+// printScanErrors reports the paths the last scan had to skip, so that an
+// incomplete scan does not go unnoticed. It writes to stderr to keep
+// machine-readable output on stdout intact.
+func printScanErrors() {
+	if len(lastScanErrors) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "\n--- Inaccessible Paths (not scanned) ---\n")
+	for _, walkErr := range lastScanErrors {
+		fmt.Fprintf(os.Stderr, "  %s: %v\n", walkErr.Path, walkErr.Err)
+	}
+	fmt.Fprintf(os.Stderr, "The scan is incomplete: %d paths could not be read. Use --ignore-errors=false to fail instead.\n", len(lastScanErrors))
+}
+
+// readRepoList reads one repository path per line from r. Relative paths are
+// resolved against the current directory; blank lines are ignored, and paths
+// without a .git directory or file are reported on stderr and skipped.
+func readRepoList(r io.Reader) ([]string, error) {
+	var repos []string
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		repoPath, err := expandPath(line)
+		if err != nil {
+			return nil, err
+		}
+		if repoPath, err = filepath.Abs(repoPath); err != nil {
+			return nil, fmt.Errorf("failed to get absolute path for %s: %w", line, err)
+		}
+		if _, err := os.Stat(filepath.Join(repoPath, ".git")); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping %s: not a Git repository\n", line)
+			continue
+		}
+		if seen[repoPath] {
+			continue
+		}
+		seen[repoPath] = true
+		repos = append(repos, repoPath)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read repository list: %w", err)
+	}
+	return repos, nil
+}
+
+// validateJobs checks a --jobs value before any work is started.
+func validateJobs(jobs int) error {
+	if jobs < 1 {
+		return fmt.Errorf("invalid --jobs value %d: must be at least 1", jobs)
+	}
+	return nil
+}
+
+// runParallel calls fn(i) for every i in [0, n) using up to jobs goroutines
+// and returns once all calls are complete. fn must route its output through an
+// output.Ordered (or its own buffer) to keep the output of parallel calls apart.
+// After Ctrl-C no further calls are started: it returns the number of indices
+// handed out, always a prefix [0, started) of the range.
+func runParallel(jobs, n int, fn func(i int)) (started int) {
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < jobs && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				fn(i)
+			}
+		}()
+	}
+	done := interruptContext().Done()
+feed:
+	for ; started < n && interruptContext().Err() == nil; started++ {
+		select {
+		case work <- started:
+		case <-done:
+			break feed
+		}
+	}
+	close(work)
+	wg.Wait()
+	return started
+}
+
+// isTerminal reports whether f is connected to a terminal rather than a pipe or file.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// formatBytes renders a byte count in binary units, e.g. "12.3 KiB".
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// dirSize returns the total size of the regular files below path.
+func dirSize(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
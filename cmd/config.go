@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/OmSingh2003/git-util/pkg/config"
+	"github.com/OmSingh2003/git-util/pkg/gitops"
+	"github.com/spf13/cobra"
+)
+
+// globalConfig is the global configuration file, loaded once per invocation by
+// loadGlobalConfig. Per-repository overrides are merged in by repoConfig.
+var globalConfig config.Config
+
+// loadGlobalConfig reads the global configuration file into globalConfig.
+func loadGlobalConfig() error {
+	cfg, err := config.LoadGlobal()
+	if err != nil {
+		return err
+	}
+	globalConfig = cfg
+	return nil
+}
+
+// repoConfig returns the effective configuration for the repository at
+// repoPath ("" for the current directory): the global configuration with the
+// .git-util.yaml at the repository root merged over it. repoPath may also be a
+// subdirectory of the working tree, e.g. when the cleaner is run from one.
+func repoConfig(repoPath string) (config.Config, error) {
+	return globalConfig.ForRepo(repoRoot(repoPath))
+}
+
+// repoRoot returns the top of the working tree containing dir. A directory
+// holding .git is taken as is; otherwise the root is asked from
+// 'git rev-parse --show-toplevel'. If that fails (e.g. in a bare repository)
+// dir is returned unchanged.
+func repoRoot(dir string) string {
+	if _, err := os.Lstat(filepath.Join(dir, ".git")); err == nil {
+		return dir
+	}
+	args := []string{"rev-parse", "--show-toplevel"}
+	if dir != "" {
+		args = append([]string{"-C", dir}, args...)
+	}
+	if root, err := gitops.RunGitCommand(args...); err == nil && root != "" {
+		return root
+	}
+	return dir
+}
+
+// Variables to hold the flag values for the config command
+var (
+	configGlobal bool
+	configRepo   string
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Read or change settings in a git-util configuration file.",
+	Long: `Reads and writes the settings of a repository's .git-util.yaml (at the root of
+the repository containing --repo, default: the current directory), or with
+--global of the global ~/.config/git-util/config.yaml.
+
+Keys:
+  main      the main branch, e.g. develop
+  exclude   branch names or glob patterns the cleaner never deletes; 'set'
+            takes a comma-separated list, 'get' prints one per line
+
+'get' shows only what the selected file sets, not the merged configuration.
+'set' keeps the other settings and comments in the file; an empty value
+removes the key.`,
+}
+
+// configGetCmd represents the 'config get' command
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a setting from the configuration file.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := config.ValidateKey(args[0]); err != nil {
+			return err
+		}
+		cmd.SilenceUsage = true
+		path, err := configFilePath()
+		if err != nil {
+			return err
+		}
+		cfg, err := config.Load(path)
+		if err != nil {
+			return err
+		}
+		value, ok, err := cfg.Get(args[0])
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("%s is not set in %s", args[0], path)
+		}
+		fmt.Println(value)
+		return nil
+	},
+}
+
+// configSetCmd represents the 'config set' command
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a setting in the configuration file.",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, value := args[0], args[1]
+		if err := config.ValidateKey(key); err != nil {
+			return err
+		}
+		cmd.SilenceUsage = true
+		path, err := configFilePath()
+		if err != nil {
+			return err
+		}
+		if err := config.Set(path, key, value); err != nil {
+			return err
+		}
+		if value == "" {
+			fmt.Printf("Removed %s from %s\n", key, path)
+		} else {
+			fmt.Printf("Set %s = %s in %s\n", key, value, path)
+		}
+		return nil
+	},
+}
+
+// configFilePath returns the file 'config get/set' work on: the global file
+// with --global, otherwise .git-util.yaml at the root of the repository
+// containing --repo.
+func configFilePath() (string, error) {
+	if configGlobal {
+		path, err := config.GlobalPath()
+		if err != nil {
+			return "", fmt.Errorf("cannot locate the global configuration file: %w", err)
+		}
+		return path, nil
+	}
+	dir, err := resolveTargetDir(configRepo)
+	if err != nil {
+		return "", err
+	}
+	root, err := gitops.RunGitCommand("-C", dir, "rev-parse", "--show-toplevel")
+	if err != nil || root == "" {
+		return "", fmt.Errorf("%s is not inside a Git working tree; use --global for the global configuration", dir)
+	}
+	return filepath.Join(root, config.RepoFileName), nil
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configGetCmd, configSetCmd)
+	configCmd.PersistentFlags().BoolVar(&configGlobal, "global", false, "Use the global configuration file instead of the repository's .git-util.yaml")
+	configCmd.PersistentFlags().StringVar(&configRepo, "repo", "", "Repository whose .git-util.yaml to use (defaults to current directory)")
+}
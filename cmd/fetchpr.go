@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/OmSingh2003/git-util/pkg/forge"
+	"github.com/OmSingh2003/git-util/pkg/gitops"
+	"github.com/spf13/cobra"
+)
+
+// Variables to hold the flag values for the fetch-pr command
+var (
+	fetchPRRemote string
+)
+
+// fetchPRCmd represents the fetch-pr command
+var fetchPRCmd = &cobra.Command{
+	Use:   "fetch-pr <number>",
+	Short: "Fetch a pull request into a local branch and check it out.",
+	Long: `Fetches the head of pull request <number> from --remote (default: origin)
+into the local branch pr-<number> of the current repository and checks it out.
+
+The ref namespace is chosen from the remote URL: GitLab merge requests are
+fetched from refs/merge-requests/<number>/head, everything else (GitHub, Gitea,
+...) from refs/pull/<number>/head.
+
+pr-<number> mirrors the pull request: running the command again updates it,
+even if the pull request was force-pushed. If pr-<number> is already checked
+out it is fast-forwarded instead, so local commits on it are never lost.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		number, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+		if err != nil || number < 1 {
+			return fmt.Errorf("invalid pull request number '%s'", args[0])
+		}
+		cmd.SilenceUsage = true // the arguments are fine from here on
+		if err := requireRepo(""); err != nil {
+			return err
+		}
+
+		// --- Resolve the Ref Namespace from the Remote URL ---
+		remoteURL, err := gitops.RemoteURL("", fetchPRRemote)
+		if err != nil {
+			return fmt.Errorf("cannot read URL of remote '%s': %w", fetchPRRemote, err)
+		}
+		host := ""
+		if remote, err := forge.ParseRemoteURL(remoteURL); err == nil {
+			host = remote.Host
+		}
+		ref := forge.PullRequestRef(host, number)
+		branch := fmt.Sprintf("pr-%d", number)
+
+		fmt.Printf("Fetching %s from %s into %s...\n", ref, fetchPRRemote, branch)
+
+		// --- Fetch and Check Out ---
+		current, _ := gitops.CurrentBranch("")
+		if current == branch {
+			if _, err := gitops.RunGitCommand("fetch", fetchPRRemote, ref); err != nil {
+				return explainPRFetchFailure(err, number, ref)
+			}
+			if _, err := gitops.RunGitCommand("merge", "--ff-only", "FETCH_HEAD"); err != nil {
+				return fmt.Errorf("%s has local commits and cannot be fast-forwarded: %w", branch, err)
+			}
+		} else {
+			// Forced, as the branch only mirrors the pull request, which may have been rewritten.
+			if _, err := gitops.RunGitCommand("fetch", fetchPRRemote, "+"+ref+":refs/heads/"+branch); err != nil {
+				return explainPRFetchFailure(err, number, ref)
+			}
+			if _, err := gitops.RunGitCommand("checkout", branch); err != nil {
+				return fmt.Errorf("fetched %s but could not check it out: %w", branch, err)
+			}
+		}
+
+		tip, err := gitops.RunGitCommand("log", "-1", "--format=%h %s")
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Checked out %s at %s\n", branch, tip)
+		return nil
+	},
+}
+
+// explainPRFetchFailure turns a missing pull request ref into a readable error.
+func explainPRFetchFailure(err error, number int, ref string) error {
+	if strings.Contains(err.Error(), "couldn't find remote ref") {
+		return fmt.Errorf("pull request #%d not found on %s (no %s)", number, fetchPRRemote, ref)
+	}
+	return fmt.Errorf("failed to fetch pull request #%d: %w", number, err)
+}
+
+func init() {
+	rootCmd.AddCommand(fetchPRCmd)
+	fetchPRCmd.Flags().StringVar(&fetchPRRemote, "remote", "origin", "Remote to fetch the pull request from")
+}
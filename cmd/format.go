@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// outputFormat is a parsed --format value: "text", "json", or a Go text/template
+// executed once per result. Commands with --null also use "null".
+type outputFormat struct {
+	kind string // "text", "json", "template" or "null"
+	tmpl *template.Template
+}
+
+// templateEscapes turns the escape sequences users type in shell-quoted
+// templates into the characters they mean.
+var templateEscapes = strings.NewReplacer(`\t`, "\t", `\n`, "\n")
+
+// parseOutputFormat validates a --format value up front so that a bad template
+// is reported before any repository is touched. sample is a zero value of the
+// type the template will be executed with; it is used to catch unknown fields.
+func parseOutputFormat(value string, sample any) (*outputFormat, error) {
+	switch strings.ToLower(value) {
+	case "", "text":
+		return &outputFormat{kind: "text"}, nil
+	case "json":
+		return &outputFormat{kind: "json"}, nil
+	}
+	if !strings.Contains(value, "{{") {
+		return nil, fmt.Errorf("invalid format '%s': must be 'text', 'json' or a Go template such as '{{.RelativePath}}'", value)
+	}
+	tmpl, err := template.New("format").Option("missingkey=error").Parse(templateEscapes.Replace(value))
+	if err != nil {
+		return nil, fmt.Errorf("invalid --format template: %w", err)
+	}
+	if err := tmpl.Execute(io.Discard, sample); err != nil {
+		return nil, fmt.Errorf("invalid --format template: %w", err)
+	}
+	return &outputFormat{kind: "template", tmpl: tmpl}, nil
+}
+
+// withNull applies a --null/-0 flag to f: the output becomes the repository
+// paths, each terminated by a NUL byte, for 'xargs -0'. It replaces the text
+// output and cannot be combined with another --format.
+func (f *outputFormat) withNull(null bool) (*outputFormat, error) {
+	if !null {
+		return f, nil
+	}
+	if f.kind != "text" {
+		return nil, errors.New("--null cannot be combined with --format")
+	}
+	return &outputFormat{kind: "null"}, nil
+}
+
+// writeNullSeparated writes each path to w followed by a NUL byte.
+func writeNullSeparated(w io.Writer, paths []string) error {
+	for _, p := range paths {
+		if _, err := io.WriteString(w, p+"\x00"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isText reports whether the human-readable output (headers, progress lines) should be printed.
+func (f *outputFormat) isText() bool {
+	return f.kind == "text"
+}
+
+// executeTemplate renders one result with the user's template, terminating it with
+// a newline unless the template already ends with one.
+func (f *outputFormat) executeTemplate(w io.Writer, data any) error {
+	var sb strings.Builder
+	if err := f.tmpl.Execute(&sb, data); err != nil {
+		return fmt.Errorf("failed to execute --format template: %w", err)
+	}
+	out := sb.String()
+	if !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+	_, err := io.WriteString(w, out)
+	return err
+}
+
+// jsonSchemaVersion is reported as "schemaVersion" at the top of every JSON
+// document. It is bumped whenever a field is renamed, removed or changes
+// meaning; adding fields does not bump it.
+const jsonSchemaVersion = 1
+
+// jsonCompact is set by the global --json-compact flag.
+var jsonCompact bool
+
+// writeJSON prints v as indented JSON, or on a single line with --json-compact.
+func writeJSON(w io.Writer, v any) error {
+	var data []byte
+	var err error
+	if jsonCompact {
+		data, err = json.Marshal(v)
+	} else {
+		data, err = json.MarshalIndent(v, "", "  ")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
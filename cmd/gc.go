@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/OmSingh2003/git-util/pkg/gitops"
+	"github.com/spf13/cobra"
+)
+
+// Variables to hold the flag values for the gc command
+var (
+	gcScan       scanFlags
+	gcAggressive bool
+)
+
+// gcCmd represents the gc command
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Run 'git gc' across multiple Git repositories and report reclaimed space.",
+	Long: `Scans a directory for Git repositories and runs 'git gc' (or 'git gc --aggressive'
+with --aggressive) in each one, reporting the size of the .git directory before
+and after so you can see how much disk space was reclaimed.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// --- Determine Target Directory ---
+		targetDir, err := resolveTargetDir(gcScan.directory)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Scanning directory: %s\n", targetDir)
+
+		// --- Find Repositories ---
+		repos, err := gcScan.findRepos(targetDir)
+		if err != nil {
+			return err
+		}
+
+		if len(repos) == 0 {
+			fmt.Println("No Git repositories found in the specified directory.")
+			return nil
+		}
+
+		fmt.Printf("\n--- Garbage Collecting Repositories ---\n")
+
+		// --- Calculate Max Path Length for Formatting ---
+		maxLen := maxDisplayLen(targetDir, repos)
+
+		gcArgs := []string{"gc", "--quiet"}
+		if gcAggressive {
+			gcArgs = append(gcArgs, "--aggressive")
+		}
+
+		// --- Process Each Repository ---
+		successCount := 0
+		failCount := 0
+		var totalBefore, totalAfter int64
+		for _, repoPath := range repos {
+			relPath := displayPath(targetDir, repoPath)
+			fmt.Printf("%-*s : ", maxLen, relPath)
+
+			gitDir, err := gitops.GitDir(repoPath)
+			if err != nil {
+				fmt.Println("FAILED")
+				fmt.Fprintf(os.Stderr, "  Error for %s: cannot locate git directory: %v\n", relPath, err)
+				failCount++
+				continue
+			}
+			before, err := dirSize(gitDir)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "  Warning: failed to measure %s: %v\n", gitDir, err)
+			}
+
+			if _, err := gitops.RunGitCommand(append([]string{"-C", repoPath}, gcArgs...)...); err != nil {
+				fmt.Println("FAILED")
+				fmt.Fprintf(os.Stderr, "  Error for %s: %v\n", relPath, err)
+				failCount++
+				continue
+			}
+
+			after, err := dirSize(gitDir)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "  Warning: failed to measure %s: %v\n", gitDir, err)
+			}
+			totalBefore += before
+			totalAfter += after
+			fmt.Printf("%s -> %s (reclaimed %s)\n", formatBytes(before), formatBytes(after), formatBytes(max(before-after, 0)))
+			successCount++
+		}
+
+		// Print summary
+		fmt.Printf("\n--- Summary ---\n")
+		fmt.Printf("  Collected: %d\n", successCount)
+		fmt.Printf("  Failed:    %d\n", failCount)
+		fmt.Printf("  Total size: %s -> %s (reclaimed %s)\n", formatBytes(totalBefore), formatBytes(totalAfter), formatBytes(max(totalBefore-totalAfter, 0)))
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(gcCmd)
+	addScanFlags(gcCmd, &gcScan)
+	gcCmd.Flags().BoolVar(&gcAggressive, "aggressive", false, "Run 'git gc --aggressive' (slower, more thorough)")
+}
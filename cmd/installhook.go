@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/OmSingh2003/git-util/pkg/gitops"
+	"github.com/spf13/cobra"
+)
+
+// Variables to hold the flag values for the install-hook command
+var (
+	hookScan    scanFlags
+	hookName    string
+	hookScript  string
+	hookSymlink bool
+	hookForce   bool
+	hookList    bool
+)
+
+// hookNamePattern matches valid hook names such as "pre-commit" or "commit-msg".
+var hookNamePattern = regexp.MustCompile(`^[a-z][a-z-]*$`)
+
+// installHookCmd represents the install-hook command
+var installHookCmd = &cobra.Command{
+	Use:   "install-hook",
+	Short: "Install a Git hook script into multiple Git repositories.",
+	Long: `Scans a directory for Git repositories and installs --script as the --hook
+hook (e.g. pre-commit) of each one, as an executable copy or, with --symlink,
+as a link to the script so later edits apply everywhere. The hooks directory
+is the one git uses, so core.hooksPath is honoured.
+
+Repositories whose hook already matches the script are skipped. A different
+existing hook is left alone unless --force is given, so hand-written hooks are
+not lost by accident.
+
+--list-hooks only reports, per repository, whether the hook is installed and,
+if --script is given, whether it matches.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !hookNamePattern.MatchString(hookName) {
+			return fmt.Errorf("invalid --hook %q: expected a hook name such as pre-commit", hookName)
+		}
+		script := ""
+		if hookScript != "" {
+			path, err := expandPath(hookScript)
+			if err != nil {
+				return err
+			}
+			if script, err = filepath.Abs(path); err != nil {
+				return fmt.Errorf("failed to get absolute path for --script: %w", err)
+			}
+			info, err := os.Stat(script)
+			if err != nil {
+				return fmt.Errorf("cannot read --script: %w", err)
+			}
+			if info.IsDir() {
+				return fmt.Errorf("--script %s is a directory", script)
+			}
+		}
+		if script == "" && !hookList {
+			return errors.New("--script is required to install a hook (or use --list-hooks)")
+		}
+
+		// --- Determine Target Directory ---
+		targetDir, err := resolveTargetDir(hookScan.directory)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Scanning directory: %s (Hook: %s)\n", targetDir, hookName)
+
+		// --- Find Repositories ---
+		repos, err := hookScan.findRepos(targetDir)
+		if err != nil {
+			return err
+		}
+
+		if len(repos) == 0 {
+			fmt.Println("No Git repositories found in the specified directory.")
+			return nil
+		}
+
+		if hookList {
+			fmt.Printf("\n--- Installed %s Hooks ---\n", hookName)
+		} else {
+			fmt.Printf("\n--- Installing %s Hooks ---\n", hookName)
+		}
+
+		// --- Calculate Max Path Length for Formatting ---
+		maxLen := maxDisplayLen(targetDir, repos)
+
+		// --- Process Each Repository ---
+		counts := make(map[string]int)
+		var installedCount, failCount int
+		for _, repoPath := range repos {
+			relPath := displayPath(targetDir, repoPath)
+			fmt.Printf("%-*s : ", maxLen, relPath)
+
+			hookPath, err := gitops.HookPath(repoPath, hookName)
+			var state string
+			if err == nil {
+				state, err = gitops.HookState(hookPath, script)
+			}
+			if err != nil {
+				fmt.Println("FAILED")
+				fmt.Fprintf(os.Stderr, "  Error for %s: %v\n", relPath, err)
+				failCount++
+				continue
+			}
+
+			if hookList {
+				fmt.Println(hookStateText(state))
+				counts[state]++
+				continue
+			}
+
+			switch {
+			case state == gitops.HookMatches:
+				fmt.Println("[Skipped: already installed]")
+				counts[state]++
+				continue
+			case state == gitops.HookDifferent && !hookForce:
+				fmt.Printf("[Skipped: a different %s hook exists; --force to replace it]\n", hookName)
+				counts[state]++
+				continue
+			}
+			if err := gitops.InstallHook(hookPath, script, hookSymlink); err != nil {
+				fmt.Println("FAILED")
+				fmt.Fprintf(os.Stderr, "  Error for %s: %v\n", relPath, err)
+				failCount++
+				continue
+			}
+			if state == gitops.HookDifferent {
+				fmt.Println("OK (replaced existing hook)")
+			} else {
+				fmt.Println("OK")
+			}
+			installedCount++
+		}
+
+		// Print summary
+		fmt.Printf("\n--- Summary ---\n")
+		if hookList {
+			if script != "" {
+				fmt.Printf("  Matching script:  %d\n", counts[gitops.HookMatches])
+				fmt.Printf("  Different hook:   %d\n", counts[gitops.HookDifferent])
+			} else {
+				fmt.Printf("  Installed:        %d\n", counts[gitops.HookInstalled])
+			}
+			fmt.Printf("  Not installed:    %d\n", counts[gitops.HookMissing])
+			fmt.Printf("  Failed:           %d\n", failCount)
+		} else {
+			fmt.Printf("  Installed:         %d\n", installedCount)
+			fmt.Printf("  Already installed: %d\n", counts[gitops.HookMatches])
+			fmt.Printf("  Different hook:    %d\n", counts[gitops.HookDifferent])
+			fmt.Printf("  Failed:            %d\n", failCount)
+		}
+
+		return nil
+	},
+}
+
+// hookStateText renders a gitops.HookState result for --list-hooks.
+func hookStateText(state string) string {
+	switch state {
+	case gitops.HookMatches:
+		return "installed (matches script)"
+	case gitops.HookDifferent:
+		return "installed (differs from script)"
+	case gitops.HookInstalled:
+		return "installed"
+	}
+	return "not installed"
+}
+
+func init() {
+	rootCmd.AddCommand(installHookCmd)
+	addScanFlags(installHookCmd, &hookScan)
+	installHookCmd.Flags().StringVar(&hookName, "hook", "pre-commit", "Name of the hook to install or list (e.g. pre-commit, commit-msg, pre-push)")
+	installHookCmd.Flags().StringVar(&hookScript, "script", "", "Script to install as the hook")
+	installHookCmd.Flags().BoolVar(&hookSymlink, "symlink", false, "Link the hook to the script instead of copying it")
+	installHookCmd.Flags().BoolVar(&hookForce, "force", false, "Replace existing hooks that differ from the script")
+	installHookCmd.Flags().BoolVar(&hookList, "list-hooks", false, "Only report which repositories have the hook installed")
+}
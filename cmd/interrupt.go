@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/OmSingh2003/git-util/pkg/gitops"
+)
+
+// interruptGrace is how long running work gets to unwind after Ctrl-C before
+// the process exits anyway.
+const interruptGrace = 5 * time.Second
+
+// interruptExitCode is the exit status after Ctrl-C, as for shells (128 + SIGINT).
+const interruptExitCode = 130
+
+// interrupt is the state of the Ctrl-C handler installed by installInterruptHandler.
+var interrupt struct {
+	ctx       context.Context
+	signalled atomic.Bool
+	handled   atomic.Bool // the command treats the interrupt as its normal end
+}
+
+// installInterruptHandler cancels the shared context on SIGINT or SIGTERM. Every
+// git command issued through gitops then stops and no new work is started, so
+// commands can print what they completed. If that takes longer than
+// interruptGrace, or a second signal arrives, the process exits immediately.
+func installInterruptHandler() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	interrupt.ctx = ctx
+	gitops.SetContext(ctx)
+
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		interrupt.signalled.Store(true)
+		cancel()
+		select {
+		case <-signals:
+		case <-time.After(interruptGrace):
+			fmt.Fprintf(os.Stderr, "\nInterrupted: git commands did not stop within %s, exiting.\n", interruptGrace)
+		}
+		stopProfile()
+		os.Exit(interruptExitCode)
+	}()
+	return ctx
+}
+
+// interruptContext returns the context cancelled on Ctrl-C.
+func interruptContext() context.Context {
+	if interrupt.ctx == nil {
+		return context.Background()
+	}
+	return interrupt.ctx
+}
+
+// interrupted reports whether Ctrl-C (or SIGTERM) was received.
+func interrupted() bool {
+	return interrupt.signalled.Load()
+}
+
+// exitInterrupted exits with interruptExitCode if the run was interrupted and
+// the command did not treat that as its normal end.
+func exitInterrupted() {
+	if interrupted() && !interrupt.handled.Load() {
+		stopProfile()
+		os.Exit(interruptExitCode)
+	}
+}
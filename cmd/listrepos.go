@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// Variables to hold the flag values for the list-repos command
+var (
+	listScan     scanFlags
+	listFormat   string
+	listNull     bool
+	listAbsolute bool
+)
+
+// listedRepo is one repository printed by list-repos.
+type listedRepo struct {
+	Path         string `json:"path"`
+	RelativePath string `json:"relativePath"`
+}
+
+// listReposReport is the JSON document printed by 'list-repos --format json'.
+type listReposReport struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	Directory     string       `json:"directory"`
+	Repos         []listedRepo `json:"repos"`
+	Count         int          `json:"count"`
+}
+
+// listReposCmd represents the list-repos command
+var listReposCmd = &cobra.Command{
+	Use:   "list-repos",
+	Short: "List the Git repositories the other commands would operate on.",
+	Long: `Scans a directory for Git repositories exactly as the other multi-repo commands
+do and prints one path per line, relative to --directory (or absolute with
+--absolute), and nothing else. Use it to check which repositories a
+destructive command would touch and how --exclude-dir, --no-submodules and
+--max-repos shape the scan.
+
+--format json prints the absolute and relative path of each repository;
+a Go template is executed once per repository, e.g. '{{.Path}}'.
+--null (-0) prints absolute paths terminated by NUL bytes, for 'xargs -0'.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := parseOutputFormat(listFormat, listedRepo{})
+		if err != nil {
+			return err
+		}
+		if format, err = format.withNull(listNull); err != nil {
+			return err
+		}
+
+		// --- Determine Target Directory ---
+		targetDir, err := resolveTargetDir(listScan.directory)
+		if err != nil {
+			return err
+		}
+
+		// --- Find Repositories ---
+		repos, err := listScan.findRepos(targetDir)
+		if err != nil {
+			return err
+		}
+
+		// --- Print Repositories ---
+		switch format.kind {
+		case "json":
+			report := listReposReport{SchemaVersion: jsonSchemaVersion, Directory: targetDir, Repos: []listedRepo{}, Count: len(repos)}
+			for _, repoPath := range repos {
+				report.Repos = append(report.Repos, listedRepo{Path: repoPath, RelativePath: displayPath(targetDir, repoPath)})
+			}
+			return writeJSON(os.Stdout, report)
+		case "null":
+			return writeNullSeparated(os.Stdout, repos)
+		case "template":
+			for _, repoPath := range repos {
+				if err := format.executeTemplate(os.Stdout, listedRepo{Path: repoPath, RelativePath: displayPath(targetDir, repoPath)}); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		if len(repos) == 0 {
+			fmt.Fprintln(os.Stderr, "No Git repositories found in the specified directory.")
+			return nil
+		}
+		for _, repoPath := range repos {
+			if listAbsolute {
+				fmt.Println(repoPath)
+			} else {
+				fmt.Println(displayPath(targetDir, repoPath))
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(listReposCmd)
+	addScanFlags(listReposCmd, &listScan)
+	listReposCmd.Flags().StringVarP(&listFormat, "format", "f", "text", "Output format: 'text', 'json' or a Go template (e.g. '{{.Path}}')")
+	listReposCmd.Flags().BoolVarP(&listNull, "null", "0", false, "Print absolute paths terminated by NUL bytes (for xargs -0)")
+	listReposCmd.Flags().BoolVar(&listAbsolute, "absolute", false, "Print absolute paths instead of paths relative to --directory")
+}
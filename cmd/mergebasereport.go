@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/OmSingh2003/git-util/pkg/gitops"
+	"github.com/spf13/cobra"
+)
+
+// Variables to hold the flag values for the merge-base-report command
+var (
+	mergeBaseMain   string
+	mergeBaseRepo   string
+	mergeBaseFormat string
+)
+
+// branchDivergence is where one branch diverged from the main branch.
+type branchDivergence struct {
+	Branch    string `json:"branch"`
+	MergeBase string `json:"mergeBase,omitempty"`
+	Date      string `json:"date,omitempty"` // committer date of the merge base, ISO 8601
+	Age       string `json:"age,omitempty"`  // the same date relative to now, e.g. "3 weeks ago"
+	Ahead     int    `json:"ahead"`          // commits on the branch since the merge base
+	Behind    int    `json:"behind"`         // commits on main since the merge base
+
+	NoCommonHistory bool   `json:"noCommonHistory,omitempty"`
+	Error           string `json:"error,omitempty"`
+}
+
+// mergeBaseReport is the JSON document printed by 'merge-base-report --format json'.
+type mergeBaseReport struct {
+	SchemaVersion int                `json:"schemaVersion"`
+	Repo          string             `json:"repo"`
+	Main          string             `json:"main"`
+	Branches      []branchDivergence `json:"branches"`
+	Behind        int                `json:"behind"` // branches with commits on main to rebase onto
+}
+
+// mergeBaseReportCmd represents the merge-base-report command
+var mergeBaseReportCmd = &cobra.Command{
+	Use:   "merge-base-report [branch...]",
+	Short: "Show where each local branch diverged from the main branch.",
+	Long: `Computes, for every local branch of a repository (or only for the branches
+given as arguments), the point where it diverged from the main branch
+('git merge-base <main> <branch>'). Each branch is reported with the merge
+base, its commit date, and how many commits the branch (AHEAD) and main
+(BEHIND) each have since then. Branches that are far behind are the ones
+that need rebasing.
+
+The main branch is --main, else the 'main' setting of the repository's
+configuration, else the detected default (main, master or origin's HEAD). It
+is not listed itself. Branches with no history in common with it are shown
+as [No common history].
+
+--repo selects the repository (default: the current directory).
+--format json prints the report as a JSON document.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format := strings.ToLower(mergeBaseFormat)
+		if format != "text" && format != "json" {
+			return fmt.Errorf("invalid format '%s': must be 'text' or 'json'", mergeBaseFormat)
+		}
+		repoPath, err := resolveTargetDir(mergeBaseRepo)
+		if err != nil {
+			return err
+		}
+		cmd.SilenceUsage = true
+		if err := requireRepo(repoPath); err != nil {
+			return err
+		}
+		if err := loadGlobalConfig(); err != nil {
+			return err
+		}
+
+		// --- Determine Main Branch ---
+		mainBranch := mergeBaseMain
+		if mainBranch == "" {
+			cfg, err := repoConfig(repoPath)
+			if err != nil {
+				return err
+			}
+			if mainBranch = cfg.Main; mainBranch == "" {
+				if mainBranch, err = gitops.DetectDefaultMainBranchIn(repoPath); err != nil {
+					return err
+				}
+			}
+		}
+		if _, err := gitops.ResolveCommit(repoPath, mainBranch); err != nil {
+			return fmt.Errorf("main branch '%s' does not exist in %s", mainBranch, repoPath)
+		}
+
+		// --- Select Branches ---
+		branches := args
+		if len(branches) == 0 {
+			local, err := gitops.LocalBranches(repoPath)
+			if err != nil {
+				return fmt.Errorf("failed to list branches: %w", err)
+			}
+			for _, b := range local {
+				if b.Name != mainBranch {
+					branches = append(branches, b.Name)
+				}
+			}
+		} else {
+			for _, branch := range branches {
+				if !gitops.RefExists(repoPath, "refs/heads/"+branch) {
+					return fmt.Errorf("no local branch named '%s' in %s", branch, repoPath)
+				}
+			}
+		}
+
+		// --- Compute Divergence ---
+		report := mergeBaseReport{SchemaVersion: jsonSchemaVersion, Repo: repoPath, Main: mainBranch, Branches: []branchDivergence{}}
+		for _, branch := range branches {
+			entry := branchDivergence{Branch: branch}
+			if err := branchDivergenceFrom(repoPath, mainBranch, &entry); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to compare %s with %s: %v\n", branch, mainBranch, err)
+				entry.Error = err.Error()
+			} else if entry.Behind > 0 {
+				report.Behind++
+			}
+			report.Branches = append(report.Branches, entry)
+		}
+
+		if format == "json" {
+			return writeJSON(os.Stdout, report)
+		}
+
+		if len(report.Branches) == 0 {
+			fmt.Printf("No local branches other than %s.\n", mainBranch)
+			return nil
+		}
+		fmt.Printf("Divergence from %s:\n", mainBranch)
+		t := &table{}
+		t.addRow("BRANCH", "MERGE BASE", "DIVERGED", "AHEAD", "BEHIND")
+		for _, entry := range report.Branches {
+			switch {
+			case entry.Error != "":
+				t.addRow(entry.Branch, "[Error]")
+				continue
+			case entry.NoCommonHistory:
+				t.addRow(entry.Branch, "[No common history]")
+				continue
+			}
+			t.addRow(entry.Branch, entry.MergeBase[:min(7, len(entry.MergeBase))], entry.Age,
+				strconv.Itoa(entry.Ahead), strconv.Itoa(entry.Behind))
+		}
+		t.write(os.Stdout, "  ")
+		fmt.Printf("\n%d of %d branches are behind %s and may need rebasing.\n", report.Behind, len(report.Branches), mainBranch)
+		return nil
+	},
+}
+
+// branchDivergenceFrom fills in entry with the merge base of entry.Branch and
+// mainBranch in the repository at repoPath and the commits each side has
+// gained since. Unrelated histories only set entry.NoCommonHistory.
+func branchDivergenceFrom(repoPath, mainBranch string, entry *branchDivergence) error {
+	branchRef := "refs/heads/" + entry.Branch
+	base, err := gitops.MergeBase(repoPath, mainBranch, branchRef)
+	if errors.Is(err, gitops.ErrNoMergeBase) {
+		entry.NoCommonHistory = true
+		return nil
+	} else if err != nil {
+		return err
+	}
+	entry.MergeBase = base
+	if entry.Age, entry.Date, err = gitops.CommitDate(repoPath, base); err != nil {
+		return err
+	}
+	if entry.Ahead, err = gitops.CountCommits(repoPath, base, branchRef); err != nil {
+		return err
+	}
+	entry.Behind, err = gitops.CountCommits(repoPath, base, mainBranch)
+	return err
+}
+
+func init() {
+	rootCmd.AddCommand(mergeBaseReportCmd)
+	mergeBaseReportCmd.Flags().StringVarP(&mergeBaseMain, "main", "m", "", "Branch to compare against (defaults to the configured or detected main branch)")
+	mergeBaseReportCmd.Flags().StringVar(&mergeBaseRepo, "repo", "", "Repository to inspect (defaults to current directory)")
+	mergeBaseReportCmd.Flags().StringVarP(&mergeBaseFormat, "format", "f", "text", "Output format: 'text' or 'json'")
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/OmSingh2003/git-util/pkg/gitops"
+	"github.com/spf13/cobra"
+)
+
+// Variables to hold the flag values for the merged command
+var (
+	mergedInto   string
+	mergedRepo   string
+	mergedFormat string
+)
+
+// mergedBranch is one branch listed by the merged command.
+type mergedBranch struct {
+	Branch  string `json:"branch"`
+	Current bool   `json:"current"`
+}
+
+// mergedReport is the JSON document printed by 'merged --format json'.
+type mergedReport struct {
+	SchemaVersion int            `json:"schemaVersion"`
+	Repo          string         `json:"repo"`
+	Into          string         `json:"into"`
+	Branches      []mergedBranch `json:"branches"`
+	Count         int            `json:"count"`
+}
+
+// mergedCmd represents the merged command
+var mergedCmd = &cobra.Command{
+	Use:   "merged",
+	Short: "List the local branches merged into a ref, without deleting anything.",
+	Long: `Lists the local branches of a repository whose tips are reachable from --into
+(a branch, tag or commit), i.e. that are fully merged into it. Nothing is
+deleted or changed, so it is safe to run from scripts; the branch cleaner
+(the root command) is the place to act on the result.
+
+Unlike the cleaner, the current branch is listed too (marked with '*'); only
+--into itself is left out. Protected branches, .git-util.yaml excludes and
+--author do not apply here.
+
+--repo selects the repository (default: the current directory).
+--format json prints the list as a JSON document.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if mergedInto == "" {
+			return errors.New("--into is required")
+		}
+		format := strings.ToLower(mergedFormat)
+		if format != "text" && format != "json" {
+			return fmt.Errorf("invalid format '%s': must be 'text' or 'json'", mergedFormat)
+		}
+		repoPath, err := resolveTargetDir(mergedRepo)
+		if err != nil {
+			return err
+		}
+		cmd.SilenceUsage = true
+		if err := requireRepo(repoPath); err != nil {
+			return err
+		}
+		if _, err := gitops.RunGitCommand("-C", repoPath, "rev-parse", "--verify", "--quiet", mergedInto+"^{commit}"); err != nil {
+			return fmt.Errorf("'%s' does not name a commit in %s", mergedInto, repoPath)
+		}
+
+		names, err := gitops.MergedBranches(repoPath, mergedInto)
+		if err != nil {
+			return fmt.Errorf("failed to list merged branches: %w", err)
+		}
+		current, _ := gitops.CurrentBranch(repoPath)
+
+		report := mergedReport{SchemaVersion: jsonSchemaVersion, Repo: repoPath, Into: mergedInto, Branches: []mergedBranch{}}
+		for _, name := range names {
+			if name == mergedInto {
+				continue // trivially merged into itself
+			}
+			report.Branches = append(report.Branches, mergedBranch{Branch: name, Current: name == current})
+		}
+		report.Count = len(report.Branches)
+
+		if format == "json" {
+			return writeJSON(os.Stdout, report)
+		}
+
+		if len(report.Branches) == 0 {
+			fmt.Printf("No local branches are merged into %s.\n", mergedInto)
+			return nil
+		}
+		fmt.Printf("Local branches merged into %s:\n", mergedInto)
+		for _, b := range report.Branches {
+			marker := " "
+			if b.Current {
+				marker = "*"
+			}
+			fmt.Printf("%s %s\n", marker, b.Branch)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mergedCmd)
+	mergedCmd.Flags().StringVar(&mergedInto, "into", "", "Ref the branches must be merged into (branch, tag or commit)")
+	mergedCmd.Flags().StringVar(&mergedRepo, "repo", "", "Repository to inspect (defaults to current directory)")
+	mergedCmd.Flags().StringVarP(&mergedFormat, "format", "f", "text", "Output format: 'text' or 'json'")
+}
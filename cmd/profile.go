@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"strings"
+	"sync"
+)
+
+// profileSpec is the hidden --profile flag: "<kind>" or "<kind>=<file>".
+var profileSpec string
+
+// profiling is the profile started by --profile and the file it is written to.
+var profiling struct {
+	mu   sync.Mutex // stopProfile may also be called by the Ctrl-C handler
+	kind string
+	file *os.File
+}
+
+// startProfile starts the profiler selected by --profile, if given. "cpu" and
+// "trace" record for the whole run; "mem" writes a heap profile when the
+// command finishes. Without "=<file>" the data goes to git-util.<kind>.pprof
+// (git-util.trace.out for a trace).
+func startProfile() error {
+	if profileSpec == "" {
+		return nil
+	}
+	kind, path, _ := strings.Cut(profileSpec, "=")
+	kind = strings.ToLower(strings.TrimSpace(kind))
+	if kind != "cpu" && kind != "mem" && kind != "trace" {
+		return fmt.Errorf("invalid --profile '%s': must be 'cpu', 'mem' or 'trace', optionally followed by =<file>", profileSpec)
+	}
+	if path == "" {
+		path = "git-util." + kind + ".pprof"
+		if kind == "trace" {
+			path = "git-util.trace.out"
+		}
+	}
+	path, err := expandPath(path)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create profile: %w", err)
+	}
+	switch kind {
+	case "cpu":
+		err = pprof.StartCPUProfile(f)
+	case "trace":
+		err = trace.Start(f)
+	}
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to start %s profile: %w", kind, err)
+	}
+	profiling.kind, profiling.file = kind, f
+	return nil
+}
+
+// stopProfile stops the --profile profiler and closes its file. It must run
+// before the process exits, or the profile is truncated; calls after the
+// first do nothing.
+func stopProfile() {
+	profiling.mu.Lock()
+	defer profiling.mu.Unlock()
+	if profiling.file == nil {
+		return
+	}
+	switch profiling.kind {
+	case "cpu":
+		pprof.StopCPUProfile()
+	case "trace":
+		trace.Stop()
+	case "mem":
+		runtime.GC() // up-to-date statistics of what is still allocated
+		if err := pprof.WriteHeapProfile(profiling.file); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to write memory profile: %v\n", err)
+		}
+	}
+	if err := profiling.file.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to close profile: %v\n", err)
+	}
+	profiling.file = nil
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// progressBarWidth is the number of cells in the drawn bar.
+const progressBarWidth = 20
+
+// progress reports the advance of a multi-repository operation. On a terminal
+// it keeps a status line ("[####    ] 3/10 repos complete | active: api, web")
+// at the bottom of the output and prints result lines above it; otherwise it
+// only prints the result lines, one per repository as each one finishes.
+// It is safe for concurrent use.
+type progress struct {
+	mu        sync.Mutex
+	out       *os.File
+	tty       bool
+	total     int
+	completed int
+	failed    int
+	active    []string
+	drawn     bool
+	stopped   bool
+
+	// summaryLabel switches the status line to a count summary, e.g.
+	// "Syncing: 12 done, 2 failed, 6 in progress".
+	summaryLabel string
+}
+
+// newProgress returns a progress reporter for total repositories writing to out.
+func newProgress(out *os.File, total int) *progress {
+	return &progress{out: out, tty: isTerminal(out), total: total}
+}
+
+// start marks name as being worked on.
+func (p *progress) start(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.active = append(p.active, name)
+	p.redraw()
+}
+
+// finish marks name as done and prints its result line.
+func (p *progress) finish(name, line string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.completeLocked(name)
+	p.printLocked(p.out, line)
+}
+
+// complete marks name as done without printing anything, for callers that
+// print results through writer. failed is counted in the summary line.
+func (p *progress) complete(name string, failed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.completeLocked(name)
+	if failed {
+		p.failed++
+	}
+	p.redraw()
+}
+
+func (p *progress) completeLocked(name string) {
+	for i, a := range p.active {
+		if a == name {
+			p.active = append(p.active[:i], p.active[i+1:]...)
+			break
+		}
+	}
+	p.completed++
+}
+
+// writer returns an io.Writer that writes to w (stdout or stderr) above the
+// status line, e.g. as the destination of an output.Ordered.
+func (p *progress) writer(w io.Writer) io.Writer {
+	return progressWriter{p: p, w: w}
+}
+
+// progressWriter is the io.Writer returned by progress.writer.
+type progressWriter struct {
+	p *progress
+	w io.Writer
+}
+
+func (pw progressWriter) Write(b []byte) (int, error) {
+	pw.p.mu.Lock()
+	defer pw.p.mu.Unlock()
+	pw.p.clear()
+	n, err := pw.w.Write(b)
+	pw.p.redraw()
+	return n, err
+}
+
+// printErr prints an error line to stderr without garbling the status line.
+func (p *progress) printErr(line string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.printLocked(os.Stderr, line)
+}
+
+// stop removes the status line for good; output written through writer
+// afterwards appears without it.
+func (p *progress) stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clear()
+	p.stopped = true
+}
+
+func (p *progress) printLocked(w io.Writer, line string) {
+	p.clear()
+	fmt.Fprintln(w, line)
+	p.redraw()
+}
+
+// clear erases the status line if it is shown.
+func (p *progress) clear() {
+	if p.drawn {
+		fmt.Fprint(p.out, "\r\033[K")
+		p.drawn = false
+	}
+}
+
+// redraw replaces the status line with the current state.
+func (p *progress) redraw() {
+	if !p.tty || p.stopped {
+		return
+	}
+	p.clear()
+	if p.summaryLabel != "" {
+		fmt.Fprintf(p.out, "%s: %d done, %d failed, %d in progress", p.summaryLabel, p.completed-p.failed, p.failed, len(p.active))
+		p.drawn = true
+		return
+	}
+	filled := 0
+	if p.total > 0 {
+		filled = p.completed * progressBarWidth / p.total
+	}
+	line := fmt.Sprintf("[%s%s] %d/%d repos complete", strings.Repeat("#", filled), strings.Repeat(" ", progressBarWidth-filled), p.completed, p.total)
+	if len(p.active) > 0 {
+		line += " | active: " + strings.Join(p.active, ", ")
+	}
+	if runes := []rune(line); len(runes) > 100 {
+		line = string(runes[:97]) + "..."
+	}
+	fmt.Fprint(p.out, line)
+	p.drawn = true
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/OmSingh2003/git-util/pkg/gitops"
+	"github.com/spf13/cobra"
+)
+
+// Variables to hold the flag values for the prune-worktrees command
+var (
+	pruneScan   scanFlags
+	pruneDryRun bool
+)
+
+// pruneWorktreesCmd represents the prune-worktrees command
+var pruneWorktreesCmd = &cobra.Command{
+	Use:   "prune-worktrees",
+	Short: "Remove stale worktree entries from multiple Git repositories.",
+	Long: `Scans a directory for Git repositories and runs 'git worktree prune' in each
+one, removing the administrative files of worktrees whose directory no longer
+exists, and reports how many were removed per repository.
+
+Linked worktrees found by the scan share their repository's worktree list, so
+each repository is pruned only once, under the first path it was found at.
+
+Use --dry-run to list the stale worktrees (and git's reason) without removing them.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// --- Determine Target Directory ---
+		targetDir, err := resolveTargetDir(pruneScan.directory)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Scanning directory: %s\n", targetDir)
+
+		// --- Find Repositories ---
+		repos, err := pruneScan.findRepos(targetDir)
+		if err != nil {
+			return err
+		}
+
+		if len(repos) == 0 {
+			fmt.Println("No Git repositories found in the specified directory.")
+			return nil
+		}
+
+		if pruneDryRun {
+			fmt.Printf("\n--- Dry Run: Stale Worktrees ---\n")
+		} else {
+			fmt.Printf("\n--- Pruning Worktrees ---\n")
+		}
+
+		// --- Calculate Max Path Length for Formatting ---
+		maxLen := maxDisplayLen(targetDir, repos)
+
+		// --- Process Each Repository ---
+		pruned := make(map[string]string) // common git dir -> display path of the repository pruned
+		var removedCount, cleanCount, sharedCount, failCount int
+		for _, repoPath := range repos {
+			relPath := displayPath(targetDir, repoPath)
+			fmt.Printf("%-*s : ", maxLen, relPath)
+
+			commonDir, err := gitops.CommonGitDir(repoPath)
+			if err != nil {
+				fmt.Println("FAILED")
+				fmt.Fprintf(os.Stderr, "  Error for %s: %v\n", relPath, err)
+				failCount++
+				continue
+			}
+			if first, ok := pruned[commonDir]; ok {
+				fmt.Printf("[Skipped: worktree of %s]\n", first)
+				sharedCount++
+				continue
+			}
+			pruned[commonDir] = relPath
+
+			if pruneDryRun {
+				worktrees, err := gitops.ListWorktrees(repoPath)
+				if err != nil {
+					fmt.Println("FAILED")
+					fmt.Fprintf(os.Stderr, "  Error for %s: %v\n", relPath, err)
+					failCount++
+					continue
+				}
+				var stale []gitops.Worktree
+				for _, wt := range worktrees {
+					if wt.Prunable != "" {
+						stale = append(stale, wt)
+					}
+				}
+				if len(stale) == 0 {
+					fmt.Println("nothing to prune")
+					cleanCount++
+					continue
+				}
+				fmt.Printf("would prune %d\n", len(stale))
+				for _, wt := range stale {
+					fmt.Printf("  - %s (%s)\n", wt.Path, wt.Prunable)
+				}
+				removedCount += len(stale)
+				continue
+			}
+
+			removed, err := gitops.PruneWorktrees(repoPath)
+			if err != nil {
+				fmt.Println("FAILED")
+				fmt.Fprintf(os.Stderr, "  Error for %s: %v\n", relPath, err)
+				failCount++
+				continue
+			}
+			if removed == 0 {
+				fmt.Println("nothing to prune")
+				cleanCount++
+				continue
+			}
+			fmt.Printf("removed %d stale worktrees\n", removed)
+			removedCount += removed
+		}
+
+		// Print summary
+		fmt.Printf("\n--- Summary ---\n")
+		if pruneDryRun {
+			fmt.Printf("  Stale worktrees:        %d\n", removedCount)
+		} else {
+			fmt.Printf("  Worktrees removed:      %d\n", removedCount)
+		}
+		fmt.Printf("  Nothing to prune:       %d\n", cleanCount)
+		if sharedCount > 0 {
+			fmt.Printf("  Skipped (same repo):    %d\n", sharedCount)
+		}
+		fmt.Printf("  Failed:                 %d\n", failCount)
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pruneWorktreesCmd)
+	addScanFlags(pruneWorktreesCmd, &pruneScan)
+	pruneWorktreesCmd.Flags().BoolVarP(&pruneDryRun, "dry-run", "n", false, "Only list the stale worktrees that would be pruned")
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/OmSingh2003/git-util/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// Variables to hold the flag values for the refresh command
+var (
+	refreshScan   scanFlags
+	refreshDryRun bool
+	refreshJobs   int
+)
+
+// refreshCmd represents the refresh command
+var refreshCmd = &cobra.Command{
+	Use:   "refresh",
+	Short: "Pull multiple Git repositories, then delete their merged branches.",
+	Long: `Scans a directory for Git repositories and, in each one, pulls the current
+branch (as 'sync -a pull') and then runs the branch cleaner with --delete,
+reporting both results on one line per repository. This is the daily
+'sync -a pull' followed by 'git-util -D <dir> -d' in a single pass.
+
+The cleaner runs even if the pull failed or was skipped (branches already
+merged locally are still merged), and uses each repository's configured or
+detected main branch, honouring .git-util.yaml excludes. Since only the
+current branch is pulled, branches merged on the remote are found once main is
+checked out and up to date, e.g. after 'sync -a checkout-main'.
+
+Pull options such as --strategy are those of sync's defaults (fast-forward
+only). Use --dry-run to pull but only report the branches that would be
+deleted. Up to --jobs repositories are processed at the same time.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateJobs(refreshJobs); err != nil {
+			return err
+		}
+
+		// --- Determine Target Directory ---
+		targetDir, err := resolveTargetDir(refreshScan.directory)
+		if err != nil {
+			return err
+		}
+
+		if err := loadCleanerFilters(); err != nil {
+			return err
+		}
+		// The cleaner reads its options from the root command's flags.
+		deleteBranches, dryRun = true, refreshDryRun
+
+		fmt.Printf("Scanning directory: %s\n", targetDir)
+
+		// --- Find Repositories ---
+		repos, err := refreshScan.findRepos(targetDir)
+		if err != nil {
+			return err
+		}
+
+		if len(repos) == 0 {
+			fmt.Println("No Git repositories found in the specified directory.")
+			return nil
+		}
+
+		if refreshDryRun {
+			fmt.Printf("\n--- Refreshing Repositories (dry run: no branches deleted) ---\n")
+		} else {
+			fmt.Printf("\n--- Refreshing Repositories ---\n")
+		}
+
+		// --- Calculate Max Path Length for Formatting ---
+		names := refreshScan.displayNames(targetDir, repos)
+		maxLen := maxNameLen(names)
+
+		// --- Pull and Clean in Parallel ---
+		syncResults := make([]syncResult, len(repos))
+		cleanResults := make([]cleanRepoResult, len(repos))
+		out := output.NewOrdered(os.Stdout, os.Stderr, len(repos))
+		repoErrs := newRepoErrors(len(repos))
+		runParallel(refreshJobs, len(repos), func(i int) {
+			relPath := names[repos[i]]
+			task := out.Task(i)
+			defer task.Done()
+
+			pull := syncRepo(repos[i], []string{"pull"})
+			if pull.Status == "failed" {
+				message := fmt.Sprintf("(pull) %s", pull.Error)
+				if pull.Output != "" {
+					message += "\nOutput: " + pull.Output
+				}
+				repoErrs.add(task, i, relPath, "%s", message)
+			}
+			var report strings.Builder
+			clean := cleanRepo(&report, repos[i])
+			if clean.err != nil {
+				repoErrs.add(task, i, relPath, "(clean) %v", clean.err)
+			}
+			syncResults[i], cleanResults[i] = pull, clean
+
+			task.Printf("%-*s : pull %s; %s\n", maxLen, relPath, syncResultText(pull), refreshCleanText(clean))
+			if verbose && clean.candidates > 0 {
+				for _, line := range strings.Split(strings.TrimSpace(report.String()), "\n") {
+					if strings.TrimSpace(line) != "" {
+						task.Printf("    %s\n", line)
+					}
+				}
+			}
+		})
+
+		// --- Summary ---
+		var pulled, pullFailed, cleanFailed int
+		totals := &cleanTotals{}
+		for i := range repos {
+			switch syncResults[i].Status {
+			case "ok":
+				pulled++
+			case "failed":
+				pullFailed++
+			}
+			if cleanResults[i].err != nil {
+				cleanFailed++
+			}
+			totals.add(cleanResults[i])
+		}
+		setReportSummary("%d repositories: %d pulled, %d pull failures, %d branches deleted",
+			len(repos), pulled, pullFailed, totals.deleted)
+
+		fmt.Printf("\n--- Summary ---\n")
+		fmt.Printf("  Repositories:           %d\n", len(repos))
+		fmt.Printf("  Pulled:                 %d\n", pulled)
+		fmt.Printf("  Pull failed:            %d\n", pullFailed)
+		if refreshDryRun {
+			fmt.Printf("  Branches to delete:     %d\n", totals.deleted)
+		} else {
+			fmt.Printf("  Branches deleted:       %d\n", totals.deleted)
+			fmt.Printf("  Failed to delete:       %d\n", totals.failed)
+		}
+		if cleanFailed > 0 {
+			fmt.Printf("  Cleaner errors:         %d\n", cleanFailed)
+		}
+		repoErrs.write(os.Stderr)
+		return nil
+	},
+}
+
+// refreshCleanText describes the cleaner's result for one repository.
+func refreshCleanText(r cleanRepoResult) string {
+	switch {
+	case r.err != nil:
+		return "clean FAILED"
+	case r.candidates == 0:
+		return "no merged branches"
+	case dryRun:
+		return fmt.Sprintf("%d merged branches would be deleted", r.deleted)
+	}
+	text := fmt.Sprintf("%d merged branches deleted", r.deleted)
+	if r.failed > 0 {
+		text += fmt.Sprintf(", %d failed", r.failed)
+	}
+	return text
+}
+
+func init() {
+	rootCmd.AddCommand(refreshCmd)
+	addScanFlags(refreshCmd, &refreshScan)
+	refreshCmd.Flags().BoolVarP(&refreshDryRun, "dry-run", "n", false, "Pull, but only report the merged branches that would be deleted")
+	refreshCmd.Flags().IntVarP(&refreshJobs, "jobs", "j", 4, "Number of repositories to refresh in parallel")
+}
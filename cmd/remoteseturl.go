@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/OmSingh2003/git-util/pkg/gitops"
+	"github.com/spf13/cobra"
+)
+
+// Variables to hold the flag values for the remote-set-url command
+var (
+	remoteURLScan    scanFlags
+	remoteURLMatch   string
+	remoteURLReplace string
+	remoteURLDryRun  bool
+)
+
+// remoteURLChange is a remote whose URL is rewritten.
+type remoteURLChange struct {
+	remote, oldURL, newURL string
+}
+
+// remoteSetURLCmd represents the remote-set-url command
+var remoteSetURLCmd = &cobra.Command{
+	Use:   "remote-set-url",
+	Short: "Rewrite matching remote URLs across multiple Git repositories.",
+	Long: `Scans a directory for Git repositories and rewrites every remote URL that
+matches the regular expression --match, replacing the matched part with
+--replace (which may refer to groups as $1 or ${name}), using
+'git remote set-url'. This is meant for moving mirrors or swapping a host, e.g.
+
+  git-util remote-set-url --match '^https://git\.old\.example\.com/' --replace 'https://git.new.example.com/'
+
+Each changed remote is reported as old -> new. Repositories without a
+matching remote are skipped. Only the fetch URL is rewritten; a separately
+configured push URL (remote.<name>.pushurl) is left as it is.
+
+Use --dry-run to see the new URLs without changing anything.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if remoteURLMatch == "" || !cmd.Flags().Changed("replace") {
+			return errors.New("both --match and --replace are required")
+		}
+		pattern, err := regexp.Compile(remoteURLMatch)
+		if err != nil {
+			return fmt.Errorf("invalid --match pattern: %w", err)
+		}
+
+		// --- Determine Target Directory ---
+		targetDir, err := resolveTargetDir(remoteURLScan.directory)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Scanning directory: %s (Rewrite: %s -> %s)\n", targetDir, remoteURLMatch, remoteURLReplace)
+
+		// --- Find Repositories ---
+		repos, err := remoteURLScan.findRepos(targetDir)
+		if err != nil {
+			return err
+		}
+
+		if len(repos) == 0 {
+			fmt.Println("No Git repositories found in the specified directory.")
+			return nil
+		}
+
+		if remoteURLDryRun {
+			fmt.Printf("\n--- Dry Run: Remote URLs That Would Change ---\n")
+		} else {
+			fmt.Printf("\n--- Rewriting Remote URLs ---\n")
+		}
+
+		// --- Calculate Max Path Length for Formatting ---
+		maxLen := maxDisplayLen(targetDir, repos)
+		indent := strings.Repeat(" ", maxLen+3)
+
+		// --- Process Each Repository ---
+		var repoCount, remoteCount, unmatchedCount, failCount int
+		for _, repoPath := range repos {
+			relPath := displayPath(targetDir, repoPath)
+
+			changes, err := remoteURLChanges(repoPath, pattern, remoteURLReplace)
+			if err != nil {
+				fmt.Printf("%-*s : FAILED\n", maxLen, relPath)
+				fmt.Fprintf(os.Stderr, "  Error for %s: %v\n", relPath, err)
+				failCount++
+				continue
+			}
+			if len(changes) == 0 {
+				unmatchedCount++
+				continue
+			}
+
+			fmt.Printf("%-*s : ", maxLen, relPath)
+			failed := false
+			for i, change := range changes {
+				if i > 0 {
+					fmt.Print(indent)
+				}
+				fmt.Printf("%s: %s -> %s", change.remote, gitops.RedactURL(change.oldURL), gitops.RedactURL(change.newURL))
+				if remoteURLDryRun {
+					fmt.Println()
+					remoteCount++
+					continue
+				}
+				if _, err := gitops.RunGitCommand("-C", repoPath, "remote", "set-url", change.remote, change.newURL); err != nil {
+					fmt.Println(" FAILED")
+					fmt.Fprintf(os.Stderr, "  Error for %s (%s): %v\n", relPath, change.remote, err)
+					failed = true
+					continue
+				}
+				fmt.Println()
+				remoteCount++
+			}
+			if failed {
+				failCount++
+			} else {
+				repoCount++
+			}
+		}
+
+		// Print summary
+		fmt.Printf("\n--- Summary ---\n")
+		if remoteURLDryRun {
+			fmt.Printf("  Repositories to update: %d (%d remotes)\n", repoCount, remoteCount)
+		} else {
+			fmt.Printf("  Repositories updated:   %d (%d remotes)\n", repoCount, remoteCount)
+		}
+		fmt.Printf("  No matching remote:     %d\n", unmatchedCount)
+		fmt.Printf("  Failed:                 %d\n", failCount)
+
+		return nil
+	},
+}
+
+// remoteURLChanges returns the remotes of the repository at repoPath whose URL
+// matches pattern, with the URL after replacing the matches with replace.
+// Remotes whose URL would not change are left out.
+func remoteURLChanges(repoPath string, pattern *regexp.Regexp, replace string) ([]remoteURLChange, error) {
+	remotes, err := gitops.Remotes(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	var changes []remoteURLChange
+	for _, remote := range remotes {
+		url, err := gitops.RemoteURL(repoPath, remote)
+		if err != nil {
+			return nil, err
+		}
+		if !pattern.MatchString(url) {
+			continue
+		}
+		newURL := pattern.ReplaceAllString(url, replace)
+		if newURL == url {
+			continue
+		}
+		if newURL == "" {
+			return nil, fmt.Errorf("rewriting %s URL %s leaves it empty", remote, url)
+		}
+		changes = append(changes, remoteURLChange{remote: remote, oldURL: url, newURL: newURL})
+	}
+	return changes, nil
+}
+
+func init() {
+	rootCmd.AddCommand(remoteSetURLCmd)
+	addScanFlags(remoteSetURLCmd, &remoteURLScan)
+	remoteSetURLCmd.Flags().StringVar(&remoteURLMatch, "match", "", "Regular expression matched against each remote URL")
+	remoteSetURLCmd.Flags().StringVar(&remoteURLReplace, "replace", "", "Replacement for the matched part ($1 refers to the first group)")
+	remoteSetURLCmd.Flags().BoolVarP(&remoteURLDryRun, "dry-run", "n", false, "Only show the URLs that would be rewritten")
+}
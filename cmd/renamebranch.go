@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/OmSingh2003/git-util/pkg/gitops"
+	"github.com/spf13/cobra"
+)
+
+// Variables to hold the flag values for the rename-branch command
+var (
+	renameScan   scanFlags
+	renameFrom   string
+	renameTo     string
+	renameDryRun bool
+)
+
+// renameBranchCmd represents the rename-branch command
+var renameBranchCmd = &cobra.Command{
+	Use:   "rename-branch",
+	Short: "Rename a local branch in every Git repository that has it.",
+	Long: `Scans a directory for Git repositories and runs 'git branch -m <from> <to>' in
+each one that has a local branch <from>. Repositories without the branch are
+skipped, as are those where <to> already exists, so nothing is overwritten.
+
+'git branch -m' keeps the branch's upstream. If the branch tracked
+<remote>/<from> and <remote>/<to> exists, the upstream is moved to
+<remote>/<to>; otherwise it is left as it was and reported. Branches on the
+remotes themselves are not renamed.
+
+Use --dry-run to see which repositories would change.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if renameFrom == "" || renameTo == "" {
+			return errors.New("both --from and --to are required")
+		}
+		if renameFrom == renameTo {
+			return errors.New("--from and --to name the same branch")
+		}
+		if _, err := gitops.RunGitCommand("check-ref-format", "--branch", renameTo); err != nil {
+			return fmt.Errorf("'%s' is not a valid branch name", renameTo)
+		}
+
+		// --- Determine Target Directory ---
+		targetDir, err := resolveTargetDir(renameScan.directory)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Scanning directory: %s (Rename: %s -> %s)\n", targetDir, renameFrom, renameTo)
+
+		// --- Find Repositories ---
+		repos, err := renameScan.findRepos(targetDir)
+		if err != nil {
+			return err
+		}
+
+		if len(repos) == 0 {
+			fmt.Println("No Git repositories found in the specified directory.")
+			return nil
+		}
+
+		if renameDryRun {
+			fmt.Printf("\n--- Dry Run: Branches That Would Be Renamed ---\n")
+		} else {
+			fmt.Printf("\n--- Renaming Branches ---\n")
+		}
+
+		// --- Calculate Max Path Length for Formatting ---
+		maxLen := maxDisplayLen(targetDir, repos)
+
+		// --- Process Each Repository ---
+		var renamedCount, conflictCount, missingCount, failCount int
+		for _, repoPath := range repos {
+			relPath := displayPath(targetDir, repoPath)
+
+			if !gitops.RefExists(repoPath, "refs/heads/"+renameFrom) {
+				missingCount++
+				continue
+			}
+			fmt.Printf("%-*s : ", maxLen, relPath)
+			if gitops.RefExists(repoPath, "refs/heads/"+renameTo) {
+				fmt.Printf("[Skipped: '%s' already exists]\n", renameTo)
+				conflictCount++
+				continue
+			}
+			if renameDryRun {
+				fmt.Printf("would rename %s -> %s\n", renameFrom, renameTo)
+				renamedCount++
+				continue
+			}
+
+			note, err := renameBranch(repoPath, renameFrom, renameTo)
+			if err != nil {
+				fmt.Println("FAILED")
+				fmt.Fprintf(os.Stderr, "  Error for %s: %v\n", relPath, err)
+				failCount++
+				continue
+			}
+			fmt.Printf("renamed %s -> %s%s\n", renameFrom, renameTo, note)
+			renamedCount++
+		}
+
+		// Print summary
+		fmt.Printf("\n--- Summary ---\n")
+		if renameDryRun {
+			fmt.Printf("  Would rename:           %d\n", renamedCount)
+		} else {
+			fmt.Printf("  Renamed:                %d\n", renamedCount)
+		}
+		fmt.Printf("  Target already exists:  %d\n", conflictCount)
+		fmt.Printf("  Without the branch:     %d\n", missingCount)
+		fmt.Printf("  Failed:                 %d\n", failCount)
+
+		return nil
+	},
+}
+
+// renameBranch renames the local branch from to to in the repository at
+// repoPath and moves its upstream from <remote>/<from> to <remote>/<to> when
+// that remote branch exists. It returns a note on the upstream for the report.
+func renameBranch(repoPath, from, to string) (string, error) {
+	upstream, err := gitops.RunGitCommand("-C", repoPath, "rev-parse", "--abbrev-ref", from+"@{u}")
+	if err != nil {
+		upstream = "" // no upstream configured
+	}
+	if _, err := gitops.RunGitCommand("-C", repoPath, "branch", "-m", from, to); err != nil {
+		return "", err
+	}
+
+	remote, ok := strings.CutSuffix(upstream, "/"+from)
+	if upstream == "" || !ok {
+		return "", nil // untracked, or tracking a differently named branch that still applies
+	}
+	newUpstream := remote + "/" + to
+	if !gitops.RefExists(repoPath, "refs/remotes/"+newUpstream) {
+		return fmt.Sprintf(" (upstream still %s)", upstream), nil
+	}
+	if _, err := gitops.RunGitCommand("-C", repoPath, "branch", "--set-upstream-to="+newUpstream, to); err != nil {
+		return "", fmt.Errorf("renamed, but failed to move upstream to %s: %w", newUpstream, err)
+	}
+	return fmt.Sprintf(" (upstream now %s)", newUpstream), nil
+}
+
+func init() {
+	rootCmd.AddCommand(renameBranchCmd)
+	addScanFlags(renameBranchCmd, &renameScan)
+	renameBranchCmd.Flags().StringVar(&renameFrom, "from", "", "Current name of the branch")
+	renameBranchCmd.Flags().StringVar(&renameTo, "to", "", "New name of the branch")
+	renameBranchCmd.Flags().BoolVarP(&renameDryRun, "dry-run", "n", false, "Only list the repositories where the branch would be renamed")
+}
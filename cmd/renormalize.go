@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/OmSingh2003/git-util/pkg/gitops"
+	"github.com/spf13/cobra"
+)
+
+// Variables to hold the flag values for the renormalize command
+var (
+	renormalizeScan scanFlags
+	renormalizeYes  bool
+)
+
+// defaultGitattributes is written to repositories without a .gitattributes, so
+// that line endings are normalized to LF in the repository.
+const defaultGitattributes = "* text=auto\n"
+
+// renormalizeCmd represents the renormalize command
+var renormalizeCmd = &cobra.Command{
+	Use:   "renormalize",
+	Short: "Fix line endings (CRLF) across multiple Git repositories.",
+	Long: `Scans a directory for Git repositories and runs 'git add --renormalize .' in
+each one, staging the files whose line endings do not match the repository's
+.gitattributes (typically CRLF committed from Windows). Repositories without a
+.gitattributes get one containing '* text=auto', which is staged as well.
+
+The changes are only staged; review them and commit, e.g. with 'git-util
+commit -m "Normalize line endings"'. Since this modifies the index, the
+affected repositories are listed first and nothing is changed unless --yes is
+given.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// --- Determine Target Directory ---
+		targetDir, err := resolveTargetDir(renormalizeScan.directory)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Scanning directory: %s\n", targetDir)
+
+		// --- Find Repositories ---
+		repos, err := renormalizeScan.findRepos(targetDir)
+		if err != nil {
+			return err
+		}
+		var workRepos []string
+		for _, repoPath := range repos {
+			if !gitops.IsBareRepo(repoPath) {
+				workRepos = append(workRepos, repoPath)
+			}
+		}
+		if len(workRepos) == 0 {
+			fmt.Println("No Git repositories with a working tree found in the specified directory.")
+			return nil
+		}
+
+		fmt.Printf("\nThe following repositories will be renormalized ('git add --renormalize .'):\n")
+		for _, repoPath := range workRepos {
+			note := ""
+			if !hasGitattributes(repoPath) {
+				note = " (no .gitattributes: one with '* text=auto' will be added)"
+			}
+			fmt.Printf("  - %s%s\n", displayPath(targetDir, repoPath), note)
+		}
+
+		if !renormalizeYes {
+			fmt.Println("\nRun with --yes (or -y) to stage the line-ending fixes.")
+			return nil
+		}
+
+		fmt.Printf("\n--- Renormalizing Line Endings ---\n")
+
+		// --- Process Each Repository ---
+		maxLen := maxDisplayLen(targetDir, workRepos)
+		changedCount := 0
+		unchangedCount := 0
+		failCount := 0
+		for _, repoPath := range workRepos {
+			relPath := displayPath(targetDir, repoPath)
+			files, created, err := renormalizeRepo(repoPath)
+			if err != nil {
+				fmt.Printf("%-*s : FAILED\n", maxLen, relPath)
+				fmt.Fprintf(os.Stderr, "  Error for %s: %v\n", relPath, err)
+				failCount++
+				continue
+			}
+			note := ""
+			if created {
+				note = " (added .gitattributes)"
+			}
+			if len(files) == 0 {
+				fmt.Printf("%-*s : no line-ending changes%s\n", maxLen, relPath, note)
+				unchangedCount++
+				continue
+			}
+			fmt.Printf("%-*s : %d files staged%s\n", maxLen, relPath, len(files), note)
+			for _, file := range files {
+				fmt.Printf("    %s\n", file)
+			}
+			changedCount++
+		}
+
+		// Print summary
+		fmt.Printf("\n--- Summary ---\n")
+		fmt.Printf("  With changes to commit: %d\n", changedCount)
+		fmt.Printf("  Already normalized:     %d\n", unchangedCount)
+		fmt.Printf("  Failed:                 %d\n", failCount)
+		return nil
+	},
+}
+
+// hasGitattributes reports whether the repository at repoPath has a top-level .gitattributes.
+func hasGitattributes(repoPath string) bool {
+	_, err := os.Stat(filepath.Join(repoPath, ".gitattributes"))
+	return err == nil
+}
+
+// renormalizeRepo stages the line-ending fixes of the repository at repoPath,
+// first adding a default .gitattributes if there is none. It returns the files
+// that became staged, not counting changes that were staged before.
+func renormalizeRepo(repoPath string) (files []string, createdAttributes bool, err error) {
+	stagedBefore, err := stagedFiles(repoPath)
+	if err != nil {
+		return nil, false, err
+	}
+	before := make(map[string]bool, len(stagedBefore))
+	for _, file := range stagedBefore {
+		before[file] = true
+	}
+	if !hasGitattributes(repoPath) {
+		path := filepath.Join(repoPath, ".gitattributes")
+		if err := os.WriteFile(path, []byte(defaultGitattributes), 0o644); err != nil {
+			return nil, false, fmt.Errorf("failed to create .gitattributes: %w", err)
+		}
+		createdAttributes = true
+		if _, err := gitops.RunGitCommand("-C", repoPath, "add", ".gitattributes"); err != nil {
+			return nil, createdAttributes, err
+		}
+	}
+	if _, err := gitops.RunGitCommand("-C", repoPath, "add", "--renormalize", "."); err != nil {
+		if strings.Contains(err.Error(), "does not have any commits yet") {
+			return nil, createdAttributes, errors.New("repository has no commits to renormalize")
+		}
+		return nil, createdAttributes, err
+	}
+	after, err := stagedFiles(repoPath)
+	if err != nil {
+		return nil, createdAttributes, err
+	}
+	for _, file := range after {
+		if !before[file] {
+			files = append(files, file)
+		}
+	}
+	return files, createdAttributes, nil
+}
+
+// stagedFiles returns the files with staged changes in the repository at repoPath, sorted by path.
+func stagedFiles(repoPath string) ([]string, error) {
+	output, err := gitops.RunGitCommand("-C", repoPath, "diff", "--cached", "--name-only")
+	if err != nil || output == "" {
+		return nil, err
+	}
+	return strings.Split(output, "\n"), nil
+}
+
+func init() {
+	rootCmd.AddCommand(renormalizeCmd)
+	addScanFlags(renormalizeCmd, &renormalizeScan)
+	renormalizeCmd.Flags().BoolVarP(&renormalizeYes, "yes", "y", false, "Confirm staging the line-ending fixes")
+}
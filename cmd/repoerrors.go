@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/OmSingh2003/git-util/internal/output"
+	"github.com/OmSingh2003/git-util/pkg/gitops"
+)
+
+// repoError is one error or warning reported for a repository.
+type repoError struct {
+	repo    string // display path of the repository
+	message string
+}
+
+// repoErrors collects the per-repository errors and warnings of a command that
+// processes repositories in parallel, so they can be listed together in an
+// "--- Errors (N) ---" section at the end instead of scrolling past between
+// the results. Entries are kept per repository index, which each task writes
+// only for its own repository, so the section follows discovery order.
+type repoErrors struct {
+	entries [][]repoError
+}
+
+// newRepoErrors returns a repoErrors for n repositories.
+func newRepoErrors(n int) *repoErrors {
+	return &repoErrors{entries: make([][]repoError, n)}
+}
+
+// add records a message for repository i, shown as relPath, with credentials
+// in URLs masked. With --verbose it is also written to the task's error stream
+// right away, as it happens.
+func (e *repoErrors) add(task *output.Task, i int, relPath, format string, args ...any) {
+	message := gitops.RedactURL(fmt.Sprintf(format, args...))
+	e.entries[i] = append(e.entries[i], repoError{repo: relPath, message: message})
+	if verbose {
+		task.Errorf("  Error for %s: %s\n", relPath, message)
+	}
+}
+
+// count returns the number of messages recorded.
+func (e *repoErrors) count() int {
+	n := 0
+	for _, list := range e.entries {
+		n += len(list)
+	}
+	return n
+}
+
+// write prints the errors section to w, or nothing if there were no errors.
+// Continuation lines of a message (e.g. git's stderr) are indented under it,
+// leaving out blank ones to keep the section compact.
+func (e *repoErrors) write(w io.Writer) {
+	n := e.count()
+	if n == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n--- Errors (%d) ---\n", n)
+	for _, list := range e.entries {
+		for _, entry := range list {
+			lines := strings.Split(strings.TrimSpace(entry.message), "\n")
+			fmt.Fprintf(w, "  %s: %s\n", entry.repo, lines[0])
+			for _, line := range lines[1:] {
+				if strings.TrimSpace(line) != "" {
+					fmt.Fprintf(w, "    %s\n", line)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// reportOutput is the open --output file. While it is open, os.Stdout points
+// at it, so every command's regular output goes to the file unchanged.
+var reportOutput struct {
+	file     *os.File
+	terminal *os.File // the original standard output
+	summary  string   // short summary printed to the terminal, set by commands that have one
+}
+
+// openReportOutput redirects standard output to --output, if given.
+func openReportOutput() error {
+	if outputPath == "" {
+		return nil
+	}
+	path, err := expandPath(outputPath)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for --output: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to open --output file: %w", err)
+	}
+	reportOutput.file = f
+	reportOutput.terminal = os.Stdout
+	os.Stdout = f
+	return nil
+}
+
+// setReportSummary records the one-line summary shown on the terminal when the
+// report itself goes to --output.
+func setReportSummary(format string, args ...any) {
+	reportOutput.summary = fmt.Sprintf(format, args...)
+}
+
+// closeReportOutput restores standard output, closes the --output file and
+// tells the user where the report went.
+func closeReportOutput() error {
+	if reportOutput.file == nil {
+		return nil
+	}
+	os.Stdout = reportOutput.terminal
+	if err := reportOutput.file.Sync(); err != nil {
+		reportOutput.file.Close()
+		return fmt.Errorf("failed to write --output file %s: %w", reportOutput.file.Name(), err)
+	}
+	if err := reportOutput.file.Close(); err != nil {
+		return fmt.Errorf("failed to write --output file %s: %w", reportOutput.file.Name(), err)
+	}
+	fmt.Printf("Report written to %s\n", reportOutput.file.Name())
+	if reportOutput.summary != "" {
+		fmt.Printf("  %s\n", reportOutput.summary)
+	}
+	return nil
+}
+
+// repoReportFile is the JSON document written per repository by --output-dir.
+type repoReportFile struct {
+	SchemaVersion int `json:"schemaVersion"`
+	Repo          any `json:"repo"`
+}
+
+// writeRepoReport writes doc, the result for one repository, as JSON to a file
+// below dir named after the repository's display path (e.g. "team/api.json"),
+// creating the directories as needed.
+func writeRepoReport(dir, repoPath, relPath string, doc any) error {
+	path := filepath.Join(dir, repoReportName(repoPath, relPath))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for --output-dir: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to write report for %s: %w", relPath, err)
+	}
+	if err := writeJSON(f, repoReportFile{SchemaVersion: jsonSchemaVersion, Repo: doc}); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write report for %s: %w", relPath, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write report for %s: %w", relPath, err)
+	}
+	return nil
+}
+
+// repoReportName returns the report file name for a repository: its display
+// path with ".json" appended, kept inside the output directory. ".." elements
+// (repositories outside the scanned directory) become "_"; a repository shown
+// as "." is named after its directory.
+func repoReportName(repoPath, relPath string) string {
+	var parts []string
+	for _, part := range strings.Split(filepath.ToSlash(filepath.Clean(relPath)), "/") {
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			part = "_"
+		}
+		parts = append(parts, part)
+	}
+	if len(parts) == 0 {
+		parts = []string{filepath.Base(repoPath)}
+	}
+	return filepath.Join(parts...) + ".json"
+}
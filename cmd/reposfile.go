@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/OmSingh2003/git-util/pkg/gitops"
+)
+
+// reposFileHeader starts every --repos-file, recording the directory that was
+// scanned so that a cache written for another --directory is not reused.
+const reposFileHeader = "# git-util repositories under "
+
+// findReposCached implements --repos-file: it returns the repositories listed
+// in the cache if it was written for targetDir, and otherwise (or with
+// --refresh) scans targetDir and writes the result to the cache. Cached paths
+// that are no longer repositories are dropped and the cache is rewritten.
+func (f *scanFlags) findReposCached(targetDir string) ([]string, error) {
+	cachePath, err := expandPath(f.reposFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if !f.refresh {
+		cached, err := readReposFile(cachePath, targetDir)
+		switch {
+		case err == nil:
+			var repos []string
+			for _, repoPath := range cached {
+				if isRepoDir(repoPath) {
+					repos = append(repos, repoPath)
+				}
+			}
+			if stale := len(cached) - len(repos); stale > 0 {
+				fmt.Fprintf(os.Stderr, "Dropped %d repositories that no longer exist from %s.\n", stale, cachePath)
+				if err := writeReposFile(cachePath, targetDir, repos); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				}
+			}
+			fmt.Fprintf(os.Stderr, "Using %d repositories cached in %s (--refresh to rescan).\n", len(repos), cachePath)
+			return repos, nil
+		case errors.Is(err, os.ErrNotExist), errors.Is(err, errOtherReposRoot):
+			// First run, or a cache of another directory: scan below.
+		default:
+			return nil, err
+		}
+	}
+
+	repos, err := f.scanRepos(targetDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeReposFile(cachePath, targetDir, repos); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	return repos, nil
+}
+
+// errOtherReposRoot is returned by readReposFile for a cache of another directory.
+var errOtherReposRoot = errors.New("repository cache was written for another directory")
+
+// readReposFile reads the repository paths cached in path for targetDir.
+func readReposFile(path, targetDir string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	if !scanner.Scan() || scanner.Text() != reposFileHeader+targetDir {
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read --repos-file %s: %w", path, err)
+		}
+		return nil, errOtherReposRoot
+	}
+	var repos []string
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" && !strings.HasPrefix(line, "#") {
+			repos = append(repos, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read --repos-file %s: %w", path, err)
+	}
+	return repos, nil
+}
+
+// writeReposFile replaces the cache at path with repos, discovered under targetDir.
+// The file is written next to path and renamed, so a reader never sees half of it.
+func writeReposFile(path, targetDir string, repos []string) error {
+	var b strings.Builder
+	b.WriteString(reposFileHeader + targetDir + "\n")
+	for _, repoPath := range repos {
+		b.WriteString(repoPath + "\n")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for --repos-file: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0o644); err != nil {
+		return fmt.Errorf("failed to write --repos-file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write --repos-file: %w", err)
+	}
+	return nil
+}
+
+// isRepoDir reports whether repoPath still holds a repository: a working tree
+// with a .git directory or file, or a bare repository.
+func isRepoDir(repoPath string) bool {
+	if _, err := os.Stat(filepath.Join(repoPath, ".git")); err == nil {
+		return true
+	}
+	if _, err := os.Stat(repoPath); err != nil {
+		return false
+	}
+	return gitops.IsBareRepo(repoPath)
+}
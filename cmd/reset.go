@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/OmSingh2003/git-util/pkg/gitops"
+	"github.com/spf13/cobra"
+)
+
+// Variables to hold the flag values for the reset command
+var (
+	resetScan  scanFlags
+	resetHard  bool
+	resetClean bool
+	resetYes   bool
+
+	resetBackupDir string
+)
+
+// resetCmd represents the reset command
+var resetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Discard local changes across multiple Git repositories.",
+	Long: `Scans a directory for Git repositories and, for every repository with local
+changes, runs 'git reset --hard' (--hard) and/or 'git clean -fd' (--clean).
+Without --clean, only changes to tracked files count: untracked files are
+left alone and do not make a repository a candidate.
+
+This is destructive: the affected repositories are always listed first, and
+nothing is changed unless --yes is given.
+
+With --backup-dir, the changes are saved before they are discarded, under
+<backup-dir>/<timestamp>/<repository>/: changes.patch holds the changes to
+tracked files (restore with 'git apply'), untracked/ a copy of the untracked
+files that 'git clean' removes. A repository whose backup fails is left untouched.
+The backup directory must not be inside a repository being reset.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !resetHard && !resetClean {
+			return fmt.Errorf("nothing to do: specify --hard and/or --clean")
+		}
+
+		// --- Determine Target Directory ---
+		targetDir, err := resolveTargetDir(resetScan.directory)
+		if err != nil {
+			return err
+		}
+
+		var backupRoot string
+		if resetBackupDir != "" {
+			dir, err := expandPath(resetBackupDir)
+			if err != nil {
+				return err
+			}
+			if dir, err = filepath.Abs(dir); err != nil {
+				return fmt.Errorf("failed to get absolute path for backup directory: %w", err)
+			}
+			// A fresh directory per run, so earlier backups are never overwritten.
+			backupRoot = filepath.Join(dir, time.Now().Format("20060102-150405"))
+		}
+
+		fmt.Printf("Scanning directory: %s\n", targetDir)
+
+		// --- Find Repositories ---
+		repos, err := resetScan.findRepos(targetDir)
+		if err != nil {
+			return err
+		}
+
+		// --- Keep Only Repositories With Changes To Discard ---
+		// Untracked files only count when --clean removes them; 'reset --hard'
+		// alone leaves them in place.
+		hasChanges := gitops.HasTrackedChanges
+		if resetClean {
+			hasChanges = gitops.IsDirty
+		}
+		var dirtyRepos []string
+		for _, repoPath := range repos {
+			dirty, err := hasChanges(repoPath)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to get status for %s: %v\n", displayPath(targetDir, repoPath), err)
+				continue
+			}
+			if dirty {
+				dirtyRepos = append(dirtyRepos, repoPath)
+			}
+		}
+
+		if len(dirtyRepos) == 0 {
+			fmt.Println("No repositories with local changes found.")
+			return nil
+		}
+
+		// A backup inside a repository being reset would be its own untracked
+		// file, removed by 'git clean' right after it is written.
+		if backupRoot != "" {
+			for _, repoPath := range dirtyRepos {
+				if backupRoot == repoPath || strings.HasPrefix(backupRoot, repoPath+string(filepath.Separator)) {
+					cmd.SilenceUsage = true
+					return fmt.Errorf("backup directory %s is inside %s, which is being reset: choose a directory outside the repositories",
+						filepath.Dir(backupRoot), displayPath(targetDir, repoPath))
+				}
+			}
+		}
+
+		var actions []string
+		if resetHard {
+			actions = append(actions, "git reset --hard")
+		}
+		if resetClean {
+			actions = append(actions, "git clean -fd")
+		}
+
+		fmt.Printf("\nThe following repositories have local changes and will be affected (%s):\n", strings.Join(actions, ", "))
+		for _, repoPath := range dirtyRepos {
+			fmt.Printf("  - %s\n", displayPath(targetDir, repoPath))
+		}
+
+		if backupRoot != "" {
+			fmt.Printf("Changes will be backed up to %s first.\n", backupRoot)
+		}
+
+		if !resetYes {
+			fmt.Println("\nRun with --yes (or -y) to discard these changes.")
+			return nil
+		}
+
+		fmt.Printf("\n--- Discarding Local Changes ---\n")
+
+		// --- Process Each Repository ---
+		maxLen := maxDisplayLen(targetDir, dirtyRepos)
+		successCount := 0
+		failCount := 0
+		for _, repoPath := range dirtyRepos {
+			relPath := displayPath(targetDir, repoPath)
+
+			var done []string
+			var failure error
+			var backupPath string
+			if backupRoot != "" {
+				backupPath = filepath.Join(backupRoot, relPath)
+				if _, err := gitops.BackupChanges(repoPath, backupPath, resetHard, resetClean); err != nil {
+					fmt.Printf("%-*s : FAILED (backup, nothing discarded)\n", maxLen, relPath)
+					fmt.Fprintf(os.Stderr, "  Error for %s: %v\n", relPath, err)
+					failCount++
+					continue
+				}
+			}
+			if resetHard {
+				if _, err := gitops.RunGitCommand("-C", repoPath, "reset", "--hard"); err != nil {
+					failure = err
+				} else {
+					done = append(done, "reset")
+				}
+			}
+			if resetClean && failure == nil {
+				if _, err := gitops.RunGitCommand("-C", repoPath, "clean", "-fd"); err != nil {
+					failure = err
+				} else {
+					done = append(done, "cleaned")
+				}
+			}
+			if backupPath != "" && failure == nil {
+				// Never report a backup that is not there, e.g. removed through a symlinked path.
+				if _, err := os.Stat(backupPath); err != nil {
+					failure = fmt.Errorf("backup %s is missing after discarding the changes: %w", backupPath, err)
+				}
+			}
+
+			if failure != nil {
+				fmt.Printf("%-*s : FAILED", maxLen, relPath)
+				if len(done) > 0 {
+					fmt.Printf(" (after: %s)", strings.Join(done, ", "))
+				}
+				fmt.Println()
+				fmt.Fprintf(os.Stderr, "  Error for %s: %v\n", relPath, failure)
+				failCount++
+			} else {
+				fmt.Printf("%-*s : %s", maxLen, relPath, strings.Join(done, ", "))
+				if backupPath != "" {
+					fmt.Printf(" (backup: %s)", backupPath)
+				}
+				fmt.Println()
+				successCount++
+			}
+		}
+
+		// Print summary
+		fmt.Printf("\n--- Summary ---\n")
+		fmt.Printf("  Successfully processed: %d\n", successCount)
+		fmt.Printf("  Failed:                 %d\n", failCount)
+		if backupRoot != "" && successCount > 0 {
+			fmt.Printf("  Backups:                %s\n", backupRoot)
+		}
+
+		if failCount > 0 {
+			cmd.SilenceUsage = true // the failures are already reported above
+			return fmt.Errorf("%d of %d repositories failed to reset", failCount, len(dirtyRepos))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(resetCmd)
+	addScanFlags(resetCmd, &resetScan)
+	resetCmd.Flags().BoolVar(&resetHard, "hard", false, "Run 'git reset --hard' in each repository with changes to tracked files")
+	resetCmd.Flags().BoolVar(&resetClean, "clean", false, "Run 'git clean -fd' in each dirty repository")
+	resetCmd.Flags().BoolVarP(&resetYes, "yes", "y", false, "Confirm the destructive operation")
+	resetCmd.Flags().StringVar(&resetBackupDir, "backup-dir", "", "Save each repository's changes (a patch and its untracked files) under this directory before discarding them")
+}
@@ -5,14 +5,38 @@ import (
 	"os"
 	"strings"
 
-	"github.com/OmSingh2003/git-util/pkg/gitops" 
+	"github.com/OmSingh2003/git-util/pkg/gitops"
 	"github.com/spf13/cobra"
 )
 
 var (
-	mainBranchName string
 	deleteBranches bool
 	dryRun         bool
+	cleanScan      scanFlags
+	cleanJobs      int
+	batchDelete    bool
+
+	mainBranchNames []string
+	mergedIntoAny   bool
+	countOnly       bool
+	assumeMain      string
+	interactive     bool
+	showAge         bool
+
+	protectedFile       string
+	protectedFromRemote bool
+	githubToken         string
+	authorPattern       string
+
+	commandLogPath  string
+	noOptionalLocks bool
+	outputPath      string
+
+	// gitConfigs are the --git-config key=value pairs passed to every git call.
+	gitConfigs []string
+
+	// verbose enables extra diagnostics on stderr, see verbosef.
+	verbose bool
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -22,104 +46,146 @@ var rootCmd = &cobra.Command{
 	Short: "A utility tool for common Git operations.",
 	Long: `git-util helps automate and simplify various Git tasks.
 The first feature implemented is cleaning up merged local branches.
-More features might be added later via subcommands (e.g., status, sync).`,
+More features might be added later via subcommands (e.g., status, sync).
+
+By default the branch cleaner works on the repository in the current directory.
+With --directory it cleans every repository found under that directory,
+processing up to --jobs repositories in parallel. Unless --main is given, each
+repository is cleaned against its own main branch (configured or detected),
+which is shown in its header.
+
+Protected branches are never offered for deletion: list them (names or glob
+patterns, one per line) in --protected-file, and/or pass --protected-from-remote
+to ask GitHub which branches of origin are protected (token from --github-token
+or the GITHUB_TOKEN environment variable).
+
+Settings can also come from a configuration file: the global
+~/.config/git-util/config.yaml, overridden per repository by a .git-util.yaml
+at the repository root. 'main' sets the main branch (the -m flag still wins)
+and 'exclude' lists branch names or glob patterns that are never deleted.
+
+--batch-delete removes the branches of each repository with one 'git branch -d'
+call instead of one call per branch, which is much faster with many branches.
+Branches git refuses to delete are retried individually to report why.
+
+--main can be repeated to require that a branch is merged into all of the
+given branches before it is offered for deletion, e.g. '-m main -m release'.
+With --merged-into-any, being merged into one of them is enough.
+
+--main also accepts a remote-tracking branch, e.g. '-m origin/main', to clean
+against what was last fetched from the remote instead of a possibly stale
+local main; the local main itself is then never offered for deletion.
+
+--assume-main <name> uses the given branch as the main branch without checking
+that it exists and without reading the configuration or detecting a default,
+for scripts that already know it. Unlike --main it takes a single branch.
+
+--interactive (-i) shows the candidate branches as a numbered list and deletes
+only the ones picked (e.g. '1,3-5' or 'all'); -d is implied and --dry-run
+still only reports. When stdin is not a terminal, a single yes/no question for
+all candidates is asked instead.
+
+--show-age adds the date of each candidate's last commit to the listing, e.g.
+'feature-x (last commit: 2 months ago)', at the cost of a git call per branch.
+
+--author limits the cleaner to branches whose tip commit was authored by a
+matching person. The pattern is matched against the author email and name:
+a glob (e.g. '*@example.com') must match fully, anything else is a
+case-insensitive substring.
+
+--count prints only the number of branches that would be deleted (after all
+filters), across every repository with --directory, e.g. for
+'if [ "$(git-util --count)" -gt 0 ]; then ...'.`,
+	// PersistentPreRunE sets up options shared by every command.
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := startProfile(); err != nil {
+			return err
+		}
+		if err := gitops.CheckGit(); err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		globalArgs, err := gitGlobalArgs()
+		if err != nil {
+			return err
+		}
+		if len(globalArgs) > 0 {
+			// Must be installed before the command log wraps the runner.
+			gitops.SetRunner(gitops.ExecRunner{GlobalArgs: globalArgs})
+		}
+		if err := openCommandLog(); err != nil {
+			return err
+		}
+		return openReportOutput()
+	},
 	// RunE executes the logic for the root command (branch cleaner)
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// --- Step 1: Determine the target main branch ---
-		targetMainBranch := mainBranchName
-		if targetMainBranch == "" {
-			var err error
-			// Call helper from gitops package
-			targetMainBranch, err = gitops.DetectDefaultMainBranch()
-			if err != nil {
-				return fmt.Errorf("could not detect default main branch: %w", err)
-			}
+		if countOnly && deleteBranches {
+			return fmt.Errorf("--count cannot be combined with --delete")
 		}
-
-		// --- Step 2: Run `git branch --merged <target>` ---
-		// Call helper from gitops package
-		mergedBranchesOutput, err := gitops.RunGitCommand("branch", "--merged", targetMainBranch) // <-- Updated Call
-		if err != nil {
-			if strings.Contains(err.Error(), "warn: no such ref") || strings.Contains(err.Error(), "error: malformed object name") {
-				return fmt.Errorf("specified main branch '%s' not found", targetMainBranch)
-			}
-			return fmt.Errorf("failed to list merged branches: %w", err)
+		if interactive && (countOnly || cleanScan.directory != "") {
+			return fmt.Errorf("--interactive works on a single repository and cannot be combined with --count or --directory")
 		}
-
-		// --- Step 3: Parse the output ---
-		lines := strings.Split(mergedBranchesOutput, "\n")
-
-		// --- Step 4: Filter the branches ---
-		var branchesToProcess []string
-		for _, line := range lines {
-			branchName := strings.TrimSpace(line)
-			if branchName == "" {
-				continue
-			}
-			if strings.HasPrefix(branchName, "* ") {
-				continue
-			}
-			if branchName == targetMainBranch {
-				continue
+		if assumeMain != "" && len(mainBranchNames) > 0 {
+			return fmt.Errorf("--assume-main cannot be combined with --main")
+		}
+		if cleanScan.directory == "" {
+			if err := requireRepo(""); err != nil {
+				cmd.SilenceUsage = true
+				return err
 			}
-			branchesToProcess = append(branchesToProcess, branchName)
 		}
-
-		// --- Step 5: Perform action ---
-		if len(branchesToProcess) == 0 {
-			fmt.Printf("No local branches found that are merged into %s (excluding the current branch).\n", targetMainBranch)
-			return nil
+		if err := loadCleanerFilters(); err != nil {
+			return err
 		}
-
-		if !deleteBranches {
-			fmt.Printf("The following local branches are merged into %s and can potentially be deleted:\n", targetMainBranch)
-			for _, branch := range branchesToProcess {
-				fmt.Printf("  - %s\n", branch)
-			}
-			fmt.Println("\nRun with --delete flag (or -d) to remove them.")
-		} else {
-			fmt.Printf("Processing deletion for branches merged into %s...\n", targetMainBranch)
-			successCount := 0
-			failCount := 0
-			for _, branch := range branchesToProcess {
-				if dryRun {
-					fmt.Printf("[Dry Run] Would attempt to delete branch: %s\n", branch)
-					successCount++
-				} else {
-					fmt.Printf("Attempting to delete branch: %s...", branch)
-					// Call helper from gitops package
-					// We capture output in case the error message needs it, even if we don't print it on success.
-					_, err := gitops.RunGitCommand("branch", "-d", branch) // <-- Updated Call
-					if err != nil {
-						fmt.Printf(" Failed (%v)\n", err) // Error from RunGitCommand includes stderr
-						failCount++
-					} else {
-						fmt.Println(" Deleted.")
-						successCount++
-					}
-				}
-			}
-			fmt.Printf("\nSummary:\n")
-			if dryRun {
-				fmt.Printf("  Dry run complete. %d branches would have been targeted for deletion.\n", successCount)
-			} else {
-				fmt.Printf("  Successfully deleted: %d\n", successCount)
-				fmt.Printf("  Failed to delete:   %d\n", failCount)
-				if failCount > 0 {
-					fmt.Println("  (Failures might occur if a branch has unmerged changes specific to it; use 'git branch -D' manually if needed.)")
-				}
-			}
+		if countOnly {
+			return runCleanerCount()
+		}
+		if cleanScan.directory != "" {
+			return runMultiRepoCleaner()
 		}
-		return nil
+		result := cleanRepo(os.Stdout, "")
+		return result.err
 	},
 }
 
+// noOptionalLocksArgs are the git options used with --no-optional-locks: they
+// stop read-only commands such as 'git status' from taking the index lock to
+// refresh it, and from starting or querying the filesystem monitor.
+var noOptionalLocksArgs = []string{"--no-optional-locks", "-c", "core.fsmonitor=false"}
+
+// gitGlobalArgs returns the options passed to git before every command, from
+// --no-optional-locks and --git-config.
+func gitGlobalArgs() ([]string, error) {
+	var args []string
+	if noOptionalLocks {
+		args = append(args, noOptionalLocksArgs...)
+	}
+	for _, kv := range gitConfigs {
+		key, _, ok := strings.Cut(kv, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid --git-config %q: expected key=value", kv)
+		}
+		args = append(args, "-c", kv)
+	}
+	return args, nil
+}
+
 // --- Standard Cobra Functions ---
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	err := rootCmd.Execute()
+	ctx := installInterruptHandler()
+	err := rootCmd.ExecuteContext(ctx)
+	stopProfile()
+	printScanErrors()
+	closeCommandLog()
+	if outErr := closeReportOutput(); outErr != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", outErr)
+		os.Exit(1)
+	}
+	exitInterrupted()
 	if err != nil {
 		os.Exit(1)
 	}
@@ -127,12 +193,32 @@ func Execute() {
 
 // init is run by Go automatically when the package is initialized.
 func init() {
+	// Flags shared by every command.
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Print extra diagnostics, such as repositories found twice through symlinks, on stderr")
+	rootCmd.PersistentFlags().BoolVar(&jsonCompact, "json-compact", false, "Print JSON output on a single line instead of indented")
+	rootCmd.PersistentFlags().BoolVar(&noOptionalLocks, "no-optional-locks", false, "Run git with --no-optional-locks so scans do not contend for the index lock of repositories in use")
+	rootCmd.PersistentFlags().StringArrayVar(&gitConfigs, "git-config", nil, "Pass a git config option as key=value to every git command (like 'git -c'); repeatable")
+	rootCmd.PersistentFlags().StringVar(&outputPath, "output", "", "Write the command's report to this file instead of the terminal, printing only a short summary")
+	rootCmd.PersistentFlags().StringVar(&commandLogPath, "command-log", "", "Append every git command run (repo, args, exit code, duration, time) as NDJSON to this file")
+	// For contributors investigating performance, e.g. '--profile cpu=cpu.pprof'.
+	rootCmd.PersistentFlags().StringVar(&profileSpec, "profile", "", "Profile the run: 'cpu', 'mem' or 'trace', optionally followed by =<file>")
+	rootCmd.PersistentFlags().MarkHidden("profile")
+
 	// Define flags specific to the root command (branch cleaner).
-	rootCmd.Flags().StringVarP(&mainBranchName, "main", "m", "", "Specify the main branch (e.g., main, master, develop)")
+	rootCmd.Flags().StringArrayVarP(&mainBranchNames, "main", "m", nil, "Specify the main branch (e.g., main, master, develop, or a remote-tracking branch like origin/main); repeatable, a branch must then be merged into all of them")
+	rootCmd.Flags().BoolVar(&mergedIntoAny, "merged-into-any", false, "With several --main branches, treat a branch as merged if it is merged into any of them")
 	rootCmd.Flags().BoolVarP(&deleteBranches, "delete", "d", false, "Actually delete the merged branches")
+	rootCmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "Choose which of the merged branches to delete from a numbered list")
+	rootCmd.Flags().StringVar(&assumeMain, "assume-main", "", "Use this branch as the main branch without verifying it exists or detecting a default (for scripts)")
+	rootCmd.Flags().BoolVar(&showAge, "show-age", false, "Show how long ago the last commit of each listed branch was made")
+	rootCmd.Flags().BoolVar(&countOnly, "count", false, "Print only the number of branches that would be deleted")
 	rootCmd.Flags().BoolVarP(&dryRun, "dry-run", "n", false, "Show what branches would be deleted without actually deleting")
+	addScanFlags(rootCmd, &cleanScan)
+	rootCmd.Flags().Lookup("directory").Usage = "Clean every Git repository found under this directory instead of only the current one"
+	rootCmd.Flags().IntVarP(&cleanJobs, "jobs", "j", 4, "Number of repositories to clean in parallel when using --directory")
+	rootCmd.Flags().BoolVar(&batchDelete, "batch-delete", false, "Delete all branches of a repository with a single 'git branch -d' call")
+	rootCmd.Flags().StringVar(&protectedFile, "protected-file", "", "File listing branch names or glob patterns that must never be deleted")
+	rootCmd.Flags().BoolVar(&protectedFromRemote, "protected-from-remote", false, "Skip branches that are protected on origin (GitHub only)")
+	rootCmd.Flags().StringVar(&authorPattern, "author", "", "Only include branches whose tip commit author (email or name) matches this pattern")
+	rootCmd.Flags().StringVar(&githubToken, "github-token", "", "GitHub API token for --protected-from-remote (defaults to $GITHUB_TOKEN)")
 }
-
-// --- Helper Function Definitions ---
-// *** Helper function definitions (runGitCommand, detectDefaultMainBranch, findGitRepos) should be DELETED from this file ***
-// They now live in pkg/gitops/helpers.go
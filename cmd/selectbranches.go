@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// selectBranches lets the user choose which of branches to delete with
+// --interactive. On a terminal it shows a numbered list and reads a selection
+// such as "1,3-5", "all" or nothing (none). When stdin is not a terminal it
+// falls back to a single yes/no question for the whole batch. Prompts are
+// written to w; end of input selects nothing.
+func selectBranches(w io.Writer, in *os.File, branches []string) ([]string, error) {
+	reader := bufio.NewReader(in)
+	if !isTerminal(in) {
+		fmt.Fprintf(w, "The following local branches can be deleted:\n")
+		for _, branch := range branches {
+			fmt.Fprintf(w, "  - %s\n", branch)
+		}
+		fmt.Fprintf(w, "Delete these %d branches? [y/N]: ", len(branches))
+		answer, err := readAnswer(reader)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintln(w) // the answer was not echoed, so end the prompt line
+		if answer == "y" || answer == "yes" {
+			return branches, nil
+		}
+		return nil, nil
+	}
+
+	fmt.Fprintf(w, "Select the branches to delete:\n")
+	for i, branch := range branches {
+		fmt.Fprintf(w, "  %3d) %s\n", i+1, branch)
+	}
+	for {
+		fmt.Fprintf(w, "Branches to delete (e.g. 1,3-5; 'all'; empty for none): ")
+		answer, err := readAnswer(reader)
+		if err != nil {
+			return nil, err
+		}
+		selected, err := parseSelection(answer, len(branches))
+		if err != nil {
+			fmt.Fprintf(w, "  %v\n", err)
+			continue
+		}
+		var chosen []string
+		for i, branch := range branches {
+			if selected[i] {
+				chosen = append(chosen, branch)
+			}
+		}
+		return chosen, nil
+	}
+}
+
+// readAnswer reads one line of input, trimmed and lower-cased. End of input
+// counts as an empty answer.
+func readAnswer(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to read selection: %w", err)
+	}
+	return strings.ToLower(strings.TrimSpace(line)), nil
+}
+
+// parseSelection parses a comma- or space-separated list of 1-based numbers
+// and ranges ("2-4") for n choices, or "all"/"a". It returns the chosen
+// 0-based indexes.
+func parseSelection(answer string, n int) (map[int]bool, error) {
+	selected := make(map[int]bool)
+	if answer == "all" || answer == "a" {
+		for i := 0; i < n; i++ {
+			selected[i] = true
+		}
+		return selected, nil
+	}
+	for _, part := range strings.FieldsFunc(answer, func(r rune) bool { return r == ',' || r == ' ' }) {
+		lo, hi, isRange := strings.Cut(part, "-")
+		first, err := strconv.Atoi(lo)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selection %q: expected numbers or ranges such as 1,3-5", part)
+		}
+		last := first
+		if isRange {
+			if last, err = strconv.Atoi(hi); err != nil {
+				return nil, fmt.Errorf("invalid selection %q: expected numbers or ranges such as 1,3-5", part)
+			}
+		}
+		if first < 1 || last > n || first > last {
+			return nil, fmt.Errorf("invalid selection %q: choose between 1 and %d", part, n)
+		}
+		for i := first; i <= last; i++ {
+			selected[i-1] = true
+		}
+	}
+	return selected, nil
+}
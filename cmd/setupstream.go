@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/OmSingh2003/git-util/pkg/gitops"
+	"github.com/spf13/cobra"
+)
+
+// Variables to hold the flag values for the set-upstream command
+var (
+	setUpstreamScan   scanFlags
+	setUpstreamRemote string
+	setUpstreamDryRun bool
+)
+
+// setUpstreamCmd represents the set-upstream command
+var setUpstreamCmd = &cobra.Command{
+	Use:   "set-upstream",
+	Short: "Set the upstream of local branches that track nothing across multiple Git repositories.",
+	Long: `Scans a directory for Git repositories and, for every local branch without an
+upstream that has a matching remote branch <remote>/<branch>, runs
+'git branch --set-upstream-to=<remote>/<branch> <branch>'.
+
+Each repository reports the branches that were linked, those with no matching
+remote branch, and the number skipped because they already track an upstream.
+Use --dry-run to see what would be linked without changing anything.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// --- Determine Target Directory ---
+		targetDir, err := resolveTargetDir(setUpstreamScan.directory)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Scanning directory: %s (Remote: %s)\n", targetDir, setUpstreamRemote)
+
+		// --- Find Repositories ---
+		repos, err := setUpstreamScan.findRepos(targetDir)
+		if err != nil {
+			return err
+		}
+
+		if len(repos) == 0 {
+			fmt.Println("No Git repositories found in the specified directory.")
+			return nil
+		}
+
+		if setUpstreamDryRun {
+			fmt.Printf("\n--- Dry Run: Branches That Would Be Linked ---\n")
+		} else {
+			fmt.Printf("\n--- Setting Upstream Branches ---\n")
+		}
+
+		// --- Calculate Max Path Length for Formatting ---
+		maxLen := maxDisplayLen(targetDir, repos)
+
+		// --- Process Each Repository ---
+		var linkedTotal, noMatchTotal, skippedTotal, failCount int
+		for _, repoPath := range repos {
+			relPath := displayPath(targetDir, repoPath)
+			fmt.Printf("%-*s : ", maxLen, relPath)
+
+			branches, err := gitops.LocalBranches(repoPath)
+			if err != nil {
+				fmt.Println("FAILED")
+				fmt.Fprintf(os.Stderr, "  Error for %s: %v\n", relPath, err)
+				failCount++
+				continue
+			}
+
+			var linked, noMatch []string
+			var linkErrors []error
+			skipped := 0
+			for _, b := range branches {
+				if b.Upstream != "" {
+					skipped++
+					continue
+				}
+				upstream := setUpstreamRemote + "/" + b.Name
+				if !gitops.RefExists(repoPath, "refs/remotes/"+upstream) {
+					noMatch = append(noMatch, b.Name)
+					continue
+				}
+				if !setUpstreamDryRun {
+					if _, err := gitops.RunGitCommand("-C", repoPath, "branch", "--set-upstream-to="+upstream, b.Name); err != nil {
+						linkErrors = append(linkErrors, fmt.Errorf("linking %s: %w", b.Name, err))
+						continue
+					}
+				}
+				linked = append(linked, b.Name)
+			}
+
+			var parts []string
+			if len(linked) > 0 {
+				verb := "linked"
+				if setUpstreamDryRun {
+					verb = "would link"
+				}
+				parts = append(parts, fmt.Sprintf("%s %s", verb, strings.Join(linked, ", ")))
+			}
+			if len(noMatch) > 0 {
+				parts = append(parts, fmt.Sprintf("no remote match: %s", strings.Join(noMatch, ", ")))
+			}
+			if len(linkErrors) > 0 {
+				parts = append(parts, fmt.Sprintf("%d FAILED", len(linkErrors)))
+			}
+			if skipped > 0 {
+				parts = append(parts, fmt.Sprintf("%d already tracking", skipped))
+			}
+			if len(parts) == 0 {
+				parts = append(parts, "no local branches")
+			}
+			fmt.Println(strings.Join(parts, "; "))
+			for _, err := range linkErrors {
+				fmt.Fprintf(os.Stderr, "  Error for %s: %v\n", relPath, err)
+			}
+			failCount += len(linkErrors)
+
+			linkedTotal += len(linked)
+			noMatchTotal += len(noMatch)
+			skippedTotal += skipped
+		}
+
+		// Print summary
+		fmt.Printf("\n--- Summary ---\n")
+		if setUpstreamDryRun {
+			fmt.Printf("  Would link:       %d\n", linkedTotal)
+		} else {
+			fmt.Printf("  Linked:           %d\n", linkedTotal)
+		}
+		fmt.Printf("  No remote match:  %d\n", noMatchTotal)
+		fmt.Printf("  Already tracking: %d\n", skippedTotal)
+		fmt.Printf("  Failed:           %d\n", failCount)
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(setUpstreamCmd)
+	addScanFlags(setUpstreamCmd, &setUpstreamScan)
+	setUpstreamCmd.Flags().StringVar(&setUpstreamRemote, "remote", "origin", "Remote whose branches are used as upstreams")
+	setUpstreamCmd.Flags().BoolVarP(&setUpstreamDryRun, "dry-run", "n", false, "Only list the branches that would be linked")
+}
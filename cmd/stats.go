@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/OmSingh2003/git-util/pkg/gitops"
+	"github.com/spf13/cobra"
+)
+
+// Variables to hold the flag values for the stats command
+var (
+	statsScan   scanFlags
+	statsSince  string
+	statsFormat string
+)
+
+// repoStats is the commit activity of a single repository.
+type repoStats struct {
+	Path         string `json:"path"`
+	RelativePath string `json:"relativePath"`
+	Commits      int    `json:"commits"`
+	Authors      int    `json:"authors"`
+	Error        string `json:"error,omitempty"`
+}
+
+// statsSummary is the activity across all scanned repositories.
+type statsSummary struct {
+	Repos   int `json:"repos"`
+	Commits int `json:"commits"`
+	Authors int `json:"authors"` // distinct authors across all repositories
+}
+
+// statsReport is the JSON document printed by 'stats --format json'.
+type statsReport struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	Since         string       `json:"since"`
+	Repos         []repoStats  `json:"repos"`
+	Summary       statsSummary `json:"summary"`
+}
+
+// shortDurationPattern matches compact durations such as "3d" or "1w".
+var shortDurationPattern = regexp.MustCompile(`^(\d+)([hdwmy])$`)
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Summarize recent commit activity across multiple Git repositories.",
+	Long: `Scans a directory for Git repositories and reports, per repository, the
+number of commits and distinct authors on the current branch within the given
+time window, followed by a grand total.
+
+--since accepts compact durations (12h, 3d, 1w, 2m, 1y) as well as anything
+'git log --since' understands, e.g. "2024-01-01" or "last monday".`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format := strings.ToLower(statsFormat)
+		if format != "text" && format != "json" {
+			return fmt.Errorf("invalid format '%s': must be 'text' or 'json'", statsFormat)
+		}
+		since := expandSince(statsSince)
+
+		// --- Determine Target Directory ---
+		targetDir, err := resolveTargetDir(statsScan.directory)
+		if err != nil {
+			return err
+		}
+
+		if format == "text" {
+			fmt.Printf("Scanning directory: %s\n", targetDir)
+		}
+
+		// --- Find Repositories ---
+		repos, err := statsScan.findRepos(targetDir)
+		if err != nil {
+			return err
+		}
+
+		// --- Collect Activity Per Repository ---
+		report := statsReport{SchemaVersion: jsonSchemaVersion, Since: statsSince, Repos: []repoStats{}}
+		allAuthors := make(map[string]bool)
+		for _, repoPath := range repos {
+			stats := repoStats{Path: repoPath, RelativePath: displayPath(targetDir, repoPath)}
+			authors, err := gitops.CommitAuthors(repoPath, since)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to read log for %s: %v\n", stats.RelativePath, err)
+				stats.Error = err.Error()
+			}
+			repoAuthors := make(map[string]bool)
+			for _, author := range authors {
+				repoAuthors[author] = true
+				allAuthors[author] = true
+			}
+			stats.Commits = len(authors)
+			stats.Authors = len(repoAuthors)
+
+			report.Summary.Commits += stats.Commits
+			report.Repos = append(report.Repos, stats)
+		}
+		report.Summary.Repos = len(repos)
+		report.Summary.Authors = len(allAuthors)
+
+		// --- Print Report ---
+		if format == "json" {
+			return writeJSON(os.Stdout, report)
+		}
+
+		if len(repos) == 0 {
+			fmt.Println("No Git repositories found in the specified directory.")
+			return nil
+		}
+
+		fmt.Printf("\n--- Commit Activity (since %s) ---\n", statsSince)
+		maxLen := maxDisplayLen(targetDir, repos)
+		if maxLen < len("Total") {
+			maxLen = len("Total")
+		}
+		for _, stats := range report.Repos {
+			if stats.Error != "" {
+				fmt.Printf("%-*s : [Error]\n", maxLen, stats.RelativePath)
+				continue
+			}
+			fmt.Printf("%-*s : %d commits, %d authors\n", maxLen, stats.RelativePath, stats.Commits, stats.Authors)
+		}
+		fmt.Printf("\n%-*s : %d commits, %d distinct authors across %d repositories\n",
+			maxLen, "Total", report.Summary.Commits, report.Summary.Authors, report.Summary.Repos)
+
+		return nil
+	},
+}
+
+// expandSince turns compact durations like "1w" into the "1.week.ago" form git
+// understands. Any other value is passed through unchanged.
+func expandSince(since string) string {
+	m := shortDurationPattern.FindStringSubmatch(strings.TrimSpace(since))
+	if m == nil {
+		return since
+	}
+	unit := map[string]string{"h": "hours", "d": "days", "w": "weeks", "m": "months", "y": "years"}[m[2]]
+	return m[1] + "." + unit + ".ago"
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+	addScanFlags(statsCmd, &statsScan)
+	statsCmd.Flags().StringVar(&statsSince, "since", "1w", "Only count commits newer than this (e.g. 12h, 3d, 1w, 2m, 1y or a date)")
+	statsCmd.Flags().StringVarP(&statsFormat, "format", "f", "text", "Output format: 'text' or 'json'")
+}
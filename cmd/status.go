@@ -1,22 +1,87 @@
 package cmd
 
 import (
-	// Imports needed by the RunE logic:
+	"encoding/csv"
+	"errors"
 	"fmt"
-	"os" // For os.Getwd, os.Stderr, etc.
-	"path/filepath"
-	"strconv" // To convert string counts to int
+	"io"
+	"os"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
-	// Import the new gitops package
-	"github.com/OmSingh2003/git-util/pkg/gitops" // <-- Import Added
-
-	// Cobra import
+	"github.com/OmSingh2003/git-util/internal/output"
+	"github.com/OmSingh2003/git-util/pkg/forge"
+	"github.com/OmSingh2003/git-util/pkg/gitops"
 	"github.com/spf13/cobra"
 )
 
-// Variable to store the directory flag value
-var statusDirectory string
+// Variables to store the status flag values
+var (
+	statusScan   scanFlags
+	statusFormat string
+	onlyDirty    bool
+	statusFailOn []string
+	statusWatch  time.Duration
+
+	statusGroupByRemote  bool
+	statusShowRemote     bool
+	statusShowLastCommit bool
+	statusJobs           int
+	statusRemoteName     string
+
+	statusAheadBehindOnly bool
+	statusIgnoreUntracked bool
+
+	statusShowPR      bool
+	statusGitHubToken string
+
+	statusNoUpstreamOK bool
+	statusRelativeTo   string
+	onlyAhead          bool
+	onlyBehind         bool
+	statusNull         bool
+	statusSummaryOnly  bool
+	statusShowStash    bool
+	statusOutputDir    string
+)
+
+// maxOriginWidth is the longest origin URL shown in the text table; longer
+// URLs are shortened in the middle so both host and repository name stay visible.
+const maxOriginWidth = 50
+
+// maxSubjectWidth is the longest commit subject shown in the text table.
+const maxSubjectWidth = 40
+
+// validFailOnConditions lists the repository states accepted by --fail-on.
+var validFailOnConditions = []string{"dirty", "ahead", "behind", "diverged", "no-upstream", "error"}
+
+// statusSummary counts repositories by state for the JSON report.
+type statusSummary struct {
+	Repos      int `json:"repos"`
+	Dirty      int `json:"dirty"`
+	Clean      int `json:"clean"`
+	Ahead      int `json:"ahead"`
+	Behind     int `json:"behind"`
+	Diverged   int `json:"diverged"`
+	NoUpstream int `json:"noUpstream"`
+	Errors     int `json:"errors"`
+}
+
+// statusReport is the JSON document printed by 'status --format json'.
+type statusReport struct {
+	SchemaVersion int                 `json:"schemaVersion"`
+	Repos         []gitops.RepoStatus `json:"repos"`
+	Summary       statusSummary       `json:"summary"`
+}
+
+// statusSummaryReport is the JSON document printed with --summary-only: the
+// statusReport without the per-repository array.
+type statusSummaryReport struct {
+	SchemaVersion int           `json:"schemaVersion"`
+	Summary       statusSummary `json:"summary"`
+}
 
 // statusCmd represents the status command
 var statusCmd = &cobra.Command{
@@ -24,127 +89,740 @@ var statusCmd = &cobra.Command{
 	Short: "Check the status of multiple Git repositories within a directory.",
 	Long: `Scans a directory for Git repositories and reports their status,
 including uncommitted changes, untracked files, and ahead/behind status
-compared to the upstream branch.`,
+compared to the upstream branch.
+
+--ahead-behind-only reports only the branch and its upstream state, for
+checkouts known to be clean (e.g. CI runners). It still makes one 'git status'
+call per repository, but one that skips the scan for untracked files and
+submodules, which is the slow part on large working trees; the STATE column
+is left out and JSON output has dirtySkipped set instead of a dirty value.
+
+--ignore-untracked does not count untracked files as changes, so a repository
+whose only changes are files missing from .gitignore is reported Clean. JSON
+output has untrackedIgnored set to show these semantics.
+
+--show-pr adds a PR column telling, for repositories on a feature branch,
+whether the branch has an open pull request on origin: [PR #123 open] or
+[No PR]. Only GitHub (including Enterprise Server) is supported; other hosts
+show '?'. The API token comes from --github-token or $GITHUB_TOKEN; without
+one only public repositories can be queried, at a low rate limit.
+
+--remote-name counts ahead/behind against the default branch of the named
+remote instead of the branch's upstream, e.g. '--remote-name upstream' to
+compare a fork with the canonical repository. The default branch is the one
+refs/remotes/<remote>/HEAD points at, else <remote>/main or <remote>/master.
+Repositories where it cannot be resolved fall back to @{u} with a warning and
+are marked (@{u}); JSON output has compareRef, or compareError on fallback.
+
+--format selects the output: 'text' (default), 'json', 'csv', or a Go template
+that is executed once per repository, e.g. --format '{{.RelativePath}}\t{{.Ahead}}/{{.Behind}}'.
+csv has a header row and the columns path (as displayed), branch, dirty, ahead,
+behind and upstream; columns that do not apply to a repository are empty.
+Template fields: Path, RelativePath, Branch, Dirty, Bare, BranchCount, State,
+OriginURL, NoOrigin, HasUpstream, Upstream, Ahead, Behind, Diverged,
+LastCommitRelative, LastCommitSubject, StashCount, CompareRef, CompareError,
+DirtySkipped, UntrackedIgnored, PullRequest, PullRequestState, StatusError,
+UpstreamError.
+
+--output-dir writes each listed repository's status as its own JSON file into
+the given directory, named after the repository's displayed path (e.g.
+team/api.json), in addition to the normal output. Directories are created as
+needed and existing files are replaced, so a repository's state can be diffed
+between runs.
+
+Bare repositories (no working tree) are marked [Bare] with their number of
+local branches; working tree and upstream checks are skipped for them.
+
+Repositories with an unfinished merge or rebase are flagged [MERGING] or
+[REBASING] (State "merging"/"rebasing" in JSON and templates).
+
+--only-dirty limits the output to repositories with uncommitted or untracked
+changes, regardless of their ahead/behind state. --only-ahead and --only-behind
+limit it to repositories with commits to push or to pull. The filters combine
+as an intersection: a repository is listed only if it matches every one given,
+e.g. '--only-ahead --only-behind' lists the diverged ones. The summary still
+counts every scanned repository.
+
+Warnings about individual repositories (e.g. a status or ahead/behind count
+that could not be read) are listed on stderr at the end, in an
+'--- Errors (N) ---' section. --verbose also prints each one as it happens.
+
+--fail-on makes the command exit with a non-zero status when any scanned
+repository is in one of the given states (comma-separated: dirty, ahead,
+behind, diverged, no-upstream, error), e.g. '--only-dirty --fail-on dirty' in a
+hook. A repository is diverged when it is both ahead of and behind its
+upstream, which usually means the upstream was force-pushed; it is marked
+[Diverged] in the table. With --no-upstream-ok, branches without an upstream
+are treated as intentionally local: they never match --fail-on, not even
+'--fail-on no-upstream', and are still listed as No Upstream.
+
+--show-remote adds each repository's origin URL to the table (shortened if
+long) and to the JSON output as originUrl; repositories without an origin
+remote are flagged [No Origin] (noOrigin in JSON).
+
+--show-last-commit adds the relative date and subject of each repository's
+HEAD commit (lastCommitRelative and lastCommitSubject in JSON). It costs one
+extra git call per repository, so it is off by default.
+
+--show-stash adds a [N stashed] marker to repositories with stash entries
+(stashCount in JSON), to find parked work. It costs one extra git call per
+repository.
+
+--group-by-remote groups the text output under the host of each repository's
+origin remote (e.g. github.com, gitlab.internal).
+
+--relative-to shows repository paths relative to another directory than the
+scan root, e.g. '-D ~/work/team --relative-to ~' lists work/team/api. Paths
+outside it start with '../'.
+
+--dir-name-only shows just the directory name of each repository instead of its
+relative path; repositories with the same name keep enough parent directories
+to tell them apart.
+
+--null (-0) prints only the absolute paths of the listed repositories, each
+terminated by a NUL byte, for 'xargs -0'; it combines with the --only-*
+filters, e.g. 'git-util status --only-dirty -0 | xargs -0 -n1 git -C'.
+
+--summary-only, with --format json, prints only schemaVersion and the summary
+counts, keeping the document small when status is collected from many machines.
+
+--stdin reads the repository paths from standard input, one per line, instead
+of scanning --directory. Paths without a .git are skipped with a warning.
+
+--jobs sets how many repositories are inspected at the same time (default 4);
+the output is the same as for a sequential run.
+
+--watch re-runs the scan at the given interval (e.g. 30s, 5m) until Ctrl-C.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// --- Determine Target Directory ---
-		targetDir := statusDirectory
-		if targetDir == "" {
-			var err error
-			targetDir, err = os.Getwd()
-			if err != nil {
-				return fmt.Errorf("failed to get current directory: %w", err)
+		// csv is specific to status, so it is not one of parseOutputFormat's kinds.
+		format := &outputFormat{kind: "csv"}
+		var err error
+		if !strings.EqualFold(statusFormat, "csv") {
+			if format, err = parseOutputFormat(statusFormat, gitops.RepoStatus{}); err != nil {
+				return err
 			}
 		}
-		var err error
-		targetDir, err = filepath.Abs(targetDir)
-		if err != nil {
-			return fmt.Errorf("failed to get absolute path for target directory: %w", err)
+		if format, err = format.withNull(statusNull); err != nil {
+			return err
+		}
+		if statusSummaryOnly && format.kind != "json" {
+			return errors.New("--summary-only requires --format json")
+		}
+		if err := validateFailOn(statusFailOn); err != nil {
+			return err
+		}
+		if err := validateJobs(statusJobs); err != nil {
+			return err
+		}
+		if statusAheadBehindOnly {
+			if onlyDirty {
+				return fmt.Errorf("--only-dirty cannot be combined with --ahead-behind-only, which does not inspect working trees")
+			}
+			for _, cond := range statusFailOn {
+				if cond == "dirty" {
+					return fmt.Errorf("--fail-on dirty cannot be combined with --ahead-behind-only, which does not inspect working trees")
+				}
+			}
 		}
 
-		fmt.Printf("Scanning directory: %s\n", targetDir)
-
-		// --- Find Git Repositories ---
-		// Call the helper function from the gitops package
-		repos, err := gitops.FindGitRepos(targetDir) // <-- Updated Call
-		if err != nil {
-			return fmt.Errorf("error finding repositories: %w", err)
+		if statusWatch > 0 {
+			return watchStatus(cmd, format)
 		}
+		return runStatus(cmd, format)
+	},
+}
 
-		if len(repos) == 0 {
-			fmt.Println("No Git repositories found in the specified directory.")
-			return nil
+// runStatus performs one status scan and prints the report in the given format.
+func runStatus(cmd *cobra.Command, format *outputFormat) error {
+	gitops.ResetCache() // every --watch cycle must see fresh repository state
+
+	// --- Determine Target Directory ---
+	targetDir, err := resolveTargetDir(statusScan.directory)
+	if err != nil {
+		return err
+	}
+	displayRoot := targetDir
+	if statusRelativeTo != "" {
+		if displayRoot, err = resolveTargetDir(statusRelativeTo); err != nil {
+			return fmt.Errorf("invalid --relative-to: %w", err)
 		}
+	}
+
+	if format.isText() {
+		fmt.Printf("Scanning directory: %s\n", targetDir)
+	}
+
+	// --- Find Git Repositories ---
+	repos, err := statusScan.findRepos(targetDir)
+	if err != nil {
+		return err
+	}
 
+	if len(repos) == 0 && format.isText() {
+		fmt.Println("No Git repositories found in the specified directory.")
+		return nil
+	}
+
+	if format.isText() {
 		fmt.Printf("\n--- Repository Status ---\n")
+	}
+
+	names := statusScan.displayNames(displayRoot, repos)
+
+	token := statusGitHubToken
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
+	}
+	if statusShowPR && token == "" && format.isText() {
+		fmt.Fprintln(os.Stderr, "Note: no GitHub token (--github-token or $GITHUB_TOKEN); pull request lookups only work for public repositories and are rate-limited.")
+	}
+
+	// --- Collect Status in Parallel ---
+	statuses := make([]gitops.RepoStatus, len(repos))
+	out := output.NewOrdered(os.Stdout, os.Stderr, len(repos))
+	repoErrs := newRepoErrors(len(repos))
+	runParallel(statusJobs, len(repos), func(i int) {
+		repoPath := repos[i]
+		task := out.Task(i)
+		defer task.Done()
 
-		// --- Calculate Max Path Length for Formatting ---
-		maxLen := 0
-		for _, repoPath := range repos {
-			relPath, _ := filepath.Rel(targetDir, repoPath)
-			if relPath == "." {
-				relPath = filepath.Base(targetDir)
+		st := gitops.GetRepoStatusWithOptions(repoPath, gitops.StatusOptions{
+			SkipWorkingTree: statusAheadBehindOnly,
+			IgnoreUntracked: statusIgnoreUntracked,
+		})
+		if interrupted() {
+			return // the results of cancelled git commands would only be errors
+		}
+		st.RelativePath = names[repoPath]
+		if statusRemoteName != "" && !st.Bare {
+			compareWithRemote(&st, statusRemoteName)
+			if st.CompareError != "" {
+				repoErrs.add(task, i, st.RelativePath, "%s; counting against @{u} instead", st.CompareError)
 			}
-			if len(relPath) > maxLen {
-				maxLen = len(relPath)
+		}
+		if statusGroupByRemote || statusShowRemote {
+			url, err := gitops.RemoteURL(repoPath, "origin")
+			switch {
+			case err == nil:
+				st.OriginURL = gitops.RedactURL(url) // never print a token embedded in the URL
+			case strings.Contains(err.Error(), "No such remote"):
+				st.NoOrigin = true
+			default:
+				repoErrs.add(task, i, st.RelativePath, "failed to read origin URL: %v", err)
 			}
 		}
+		if statusShowPR && !st.Bare {
+			if err := lookupPullRequest(&st, token); err != nil {
+				repoErrs.add(task, i, st.RelativePath, "failed to look up pull request: %v", err)
+			}
+		}
+		if statusShowLastCommit {
+			// A repository without commits simply has no last commit to show.
+			st.LastCommitRelative, st.LastCommitSubject, _ = gitops.LastCommit(repoPath)
+		}
+		if statusShowStash && !st.Bare {
+			count, err := gitops.StashCount(repoPath)
+			if err != nil {
+				repoErrs.add(task, i, st.RelativePath, "failed to list stashes: %v", err)
+			}
+			st.StashCount = count
+		}
+		if st.StatusError != "" {
+			repoErrs.add(task, i, st.RelativePath, "failed to get status: %v", st.StatusError)
+		}
+		if st.UpstreamError != "" {
+			repoErrs.add(task, i, st.RelativePath, "failed to get ahead/behind count: %v", st.UpstreamError)
+		}
+		statuses[i] = st
+	})
+
+	// --- Summarize in Discovery Order ---
+	report := statusReport{SchemaVersion: jsonSchemaVersion, Repos: []gitops.RepoStatus{}}
+	failing := 0
+	for _, st := range statuses {
+		if st.Path == "" {
+			continue // not inspected because of Ctrl-C
+		}
+		report.Summary.add(st)
+		if matchesFailOn(st, statusFailOn) {
+			failing++
+		}
+		if !matchesOnlyFilters(st) {
+			continue
+		}
+		report.Repos = append(report.Repos, st)
+	}
 
-		// --- Process Each Repository ---
-		for _, repoPath := range repos {
-			relPath, _ := filepath.Rel(targetDir, repoPath)
-			if relPath == "." {
-				relPath = filepath.Base(targetDir)
+	setReportSummary("%d repositories: %d dirty, %d ahead, %d behind, %d diverged, %d without upstream, %d errors",
+		report.Summary.Repos, report.Summary.Dirty, report.Summary.Ahead, report.Summary.Behind, report.Summary.Diverged,
+		report.Summary.NoUpstream, report.Summary.Errors)
+
+	// --- Write Per-Repository Reports ---
+	if statusOutputDir != "" {
+		dir, err := expandPath(statusOutputDir)
+		if err != nil {
+			return err
+		}
+		for _, st := range report.Repos {
+			if err := writeRepoReport(dir, st.Path, st.RelativePath, st); err != nil {
+				return err
 			}
+		}
+	}
 
-			// --- Check Working Directory Status ---
-			isDirty := false
-			// Call the helper function from the gitops package
-			statusOutput, err := gitops.RunGitCommand("-C", repoPath, "status", "--porcelain=v1") // <-- Updated Call
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to get status for %s: %v\n", relPath, err)
-				isDirty = true
-			} else if statusOutput != "" {
-				isDirty = true
+	// --- Print Machine-Readable Results ---
+	switch format.kind {
+	case "json":
+		var doc any = report
+		if statusSummaryOnly {
+			doc = statusSummaryReport{SchemaVersion: report.SchemaVersion, Summary: report.Summary}
+		}
+		if err := writeJSON(os.Stdout, doc); err != nil {
+			return err
+		}
+	case "template":
+		for _, st := range report.Repos {
+			if err := format.executeTemplate(os.Stdout, st); err != nil {
+				return err
+			}
+		}
+	case "csv":
+		if err := writeStatusCSV(os.Stdout, report.Repos); err != nil {
+			return err
+		}
+	case "null":
+		paths := make([]string, len(report.Repos))
+		for i, st := range report.Repos {
+			paths[i] = st.Path
+		}
+		if err := writeNullSeparated(os.Stdout, paths); err != nil {
+			return err
+		}
+	default:
+		if statusGroupByRemote {
+			printStatusByRemote(report.Repos)
+		} else if len(report.Repos) > 0 {
+			statusTable(report.Repos).write(os.Stdout, "")
+		}
+		if len(report.Repos) == 0 {
+			switch {
+			case onlyDirty && !onlyAhead && !onlyBehind:
+				fmt.Println("No repositories with uncommitted changes.")
+			case onlyDirty || onlyAhead || onlyBehind:
+				fmt.Println("No repositories match the --only-* filters.")
 			}
+		}
+	}
 
-			// --- Check Ahead/Behind Status ---
-			ahead := 0
-			behind := 0
-			upstreamStatus := ""
-			// Call the helper function from the gitops package
-			revOutput, err := gitops.RunGitCommand("-C", repoPath, "rev-list", "--left-right", "--count", "HEAD...@{u}") // <-- Updated Call
+	if statusOutputDir != "" && format.isText() {
+		fmt.Printf("\nWrote %d repository reports to %s\n", len(report.Repos), statusOutputDir)
+	}
+	repoErrs.write(os.Stderr)
 
-			if err != nil {
-				if strings.Contains(err.Error(), "no upstream configured") || strings.Contains(err.Error(), "unknown revision") {
-					upstreamStatus = " [No Upstream]"
-				} else {
-					fmt.Fprintf(os.Stderr, "Warning: failed to get ahead/behind count for %s: %v\n", relPath, err)
-					upstreamStatus = " [Error]"
-				}
-			} else {
-				parts := strings.Split(strings.TrimSpace(revOutput), "\t")
-				if len(parts) == 2 {
-					ahead, _ = strconv.Atoi(parts[0])
-					behind, _ = strconv.Atoi(parts[1])
-
-					if ahead > 0 && behind > 0 {
-						upstreamStatus = fmt.Sprintf(" [Ahead %d, Behind %d]", ahead, behind)
-					} else if ahead > 0 {
-						upstreamStatus = fmt.Sprintf(" [Ahead %d]", ahead)
-					} else if behind > 0 {
-						upstreamStatus = fmt.Sprintf(" [Behind %d]", behind)
-					}
-				} else {
-					upstreamStatus = " [Error Parsing Revs]"
-				}
+	if failing > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d of %d repositories matched --fail-on %s", failing, len(repos), strings.Join(statusFailOn, ","))
+	}
+	return nil
+}
+
+// watchStatus re-runs the status scan every --watch interval until interrupted.
+// Each cycle starts with a timestamped header; on a terminal the screen is cleared first.
+func watchStatus(cmd *cobra.Command, format *outputFormat) error {
+	ctx := interruptContext()
+	for {
+		if format.isText() && isTerminal(os.Stdout) {
+			fmt.Print("\033[H\033[2J") // clear screen, cursor to top-left
+		}
+		if format.isText() {
+			fmt.Printf("=== %s (every %s, Ctrl-C to stop) ===\n", time.Now().Format("2006-01-02 15:04:05"), statusWatch)
+		}
+		// A --fail-on match or scan error must not end the dashboard; report it and keep going.
+		if err := runStatus(cmd, format); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			interrupt.handled.Store(true) // Ctrl-C is how the dashboard is meant to end
+			fmt.Println()
+			return nil
+		case <-time.After(statusWatch):
+		}
+	}
+}
+
+// printStatusByRemote prints the text status lines grouped under a header per
+// origin host, in host order, with repositories lacking an origin last.
+func printStatusByRemote(statuses []gitops.RepoStatus) {
+	groups := make(map[string][]gitops.RepoStatus)
+	for _, st := range statuses {
+		host := remoteHostLabel(st.OriginURL)
+		groups[host] = append(groups[host], st)
+	}
+	hosts := make([]string, 0, len(groups))
+	for host := range groups {
+		hosts = append(hosts, host)
+	}
+	sort.Slice(hosts, func(i, j int) bool {
+		// Parenthesised labels ("(no origin)", "(local)") sort after real hosts.
+		pi, pj := strings.HasPrefix(hosts[i], "("), strings.HasPrefix(hosts[j], "(")
+		if pi != pj {
+			return pj
+		}
+		return hosts[i] < hosts[j]
+	})
+
+	for _, host := range hosts {
+		fmt.Printf("\n[%s]\n", host)
+		statusTable(groups[host]).write(os.Stdout, "  ")
+	}
+}
+
+// remoteHostLabel returns the host of an origin URL for grouping purposes.
+func remoteHostLabel(originURL string) string {
+	if originURL == "" {
+		return "(no origin)"
+	}
+	remote, err := forge.ParseRemoteURL(originURL)
+	if err != nil {
+		return "(local)"
+	}
+	return remote.Host
+}
+
+// add counts st in the summary.
+func (s *statusSummary) add(st gitops.RepoStatus) {
+	s.Repos++
+	switch {
+	case st.DirtySkipped:
+	case st.Dirty:
+		s.Dirty++
+	default:
+		s.Clean++
+	}
+	if st.Ahead > 0 {
+		s.Ahead++
+	}
+	if st.Behind > 0 {
+		s.Behind++
+	}
+	if st.Diverged {
+		s.Diverged++
+	}
+	if !st.HasUpstream && st.UpstreamError == "" && !st.Bare {
+		s.NoUpstream++
+	}
+	if st.StatusError != "" || st.UpstreamError != "" {
+		s.Errors++
+	}
+}
+
+// validateFailOn checks the --fail-on values before any repository is scanned.
+func validateFailOn(conditions []string) error {
+	for _, cond := range conditions {
+		valid := false
+		for _, v := range validFailOnConditions {
+			if cond == v {
+				valid = true
+				break
 			}
+		}
+		if !valid {
+			return fmt.Errorf("invalid --fail-on condition '%s': must be one of %s", cond, strings.Join(validFailOnConditions, ", "))
+		}
+	}
+	return nil
+}
 
-			// --- Combine and Print Status ---
-			finalStatus := ""
-			if isDirty {
-				finalStatus = "Dirty" + upstreamStatus
-			} else if upstreamStatus != "" && !strings.HasPrefix(upstreamStatus, " [Error") && upstreamStatus != " [No Upstream]" {
-				finalStatus = "Clean" + upstreamStatus
-			} else if upstreamStatus != "" {
-				finalStatus = "Clean" + upstreamStatus
-			} else {
-				finalStatus = "Clean"
+// matchesOnlyFilters reports whether st is listed under the --only-dirty,
+// --only-ahead and --only-behind filters: it must match every filter given.
+func matchesOnlyFilters(st gitops.RepoStatus) bool {
+	if onlyDirty && !st.Dirty {
+		return false
+	}
+	if onlyAhead && st.Ahead == 0 {
+		return false
+	}
+	if onlyBehind && st.Behind == 0 {
+		return false
+	}
+	return true
+}
+
+// matchesFailOn reports whether st is in any of the given --fail-on states.
+func matchesFailOn(st gitops.RepoStatus, conditions []string) bool {
+	for _, cond := range conditions {
+		switch cond {
+		case "dirty":
+			if st.Dirty {
+				return true
+			}
+		case "ahead":
+			if st.Ahead > 0 {
+				return true
+			}
+		case "behind":
+			if st.Behind > 0 {
+				return true
+			}
+		case "diverged":
+			if st.Diverged {
+				return true
+			}
+		case "no-upstream":
+			if !statusNoUpstreamOK && !st.HasUpstream && st.UpstreamError == "" && !st.Bare {
+				return true
 			}
+		case "error":
+			if st.StatusError != "" || st.UpstreamError != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
 
-			fmt.Printf("%-*s : %s\n", maxLen, relPath, finalStatus)
+// statusCSVHeader is the first row of 'status --format csv'.
+var statusCSVHeader = []string{"path", "branch", "dirty", "ahead", "behind", "upstream"}
 
-		} // End loop through repos
+// writeStatusCSV writes one CSV row per repository for spreadsheets. Fields
+// that do not apply (the working tree of a bare repository, ahead/behind
+// without an upstream) are left empty. encoding/csv quotes values containing
+// commas, quotes or newlines.
+func writeStatusCSV(w io.Writer, statuses []gitops.RepoStatus) error {
+	cw := csv.NewWriter(w)
+	cw.Write(statusCSVHeader)
+	for _, st := range statuses {
+		dirty := strconv.FormatBool(st.Dirty)
+		if st.Bare || st.DirtySkipped {
+			dirty = ""
+		}
+		upstream := st.Upstream
+		if st.CompareRef != "" {
+			upstream = st.CompareRef
+		}
+		ahead, behind := "", ""
+		if st.HasUpstream || st.CompareRef != "" {
+			ahead, behind = strconv.Itoa(st.Ahead), strconv.Itoa(st.Behind)
+		}
+		cw.Write([]string{st.RelativePath, st.Branch, dirty, ahead, behind, upstream})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// statusTable lays out the text report with one aligned row per repository:
+// path, branch, working tree state and upstream state.
+func statusTable(statuses []gitops.RepoStatus) *table {
+	t := &table{}
+	upstreamHeader := "UPSTREAM"
+	if statusRemoteName != "" {
+		upstreamHeader = "VS " + strings.ToUpper(statusRemoteName)
+	}
+	header := []string{"REPOSITORY", "BRANCH", "STATE", upstreamHeader}
+	if statusAheadBehindOnly {
+		header = []string{"REPOSITORY", "BRANCH", upstreamHeader}
+	}
+	if statusShowPR {
+		header = append(header, "PR")
+	}
+	if statusShowRemote {
+		header = append(header, "ORIGIN")
+	}
+	if statusShowLastCommit {
+		header = append(header, "LAST COMMIT")
+	}
+	t.addRow(header...)
+	for _, st := range statuses {
+		branch := st.Branch
+		if branch == "" {
+			branch = "-"
+		}
+		row := []string{st.RelativePath, branch, statusStateText(st), statusUpstreamText(st)}
+		if statusAheadBehindOnly {
+			row = []string{st.RelativePath, branch, statusUpstreamText(st)}
+		}
+		if statusShowPR {
+			row = append(row, statusPullRequestText(st))
+		}
+		if statusShowRemote {
+			row = append(row, statusOriginText(st))
+		}
+		if statusShowLastCommit {
+			row = append(row, statusLastCommitText(st))
+		}
+		t.addRow(row...)
+	}
+	return t
+}
 
+// lookupPullRequest asks the forge of origin whether the branch checked out in
+// st has an open pull request. Only feature branches are looked up: a detached
+// HEAD or the default branch is left without a PullRequestState. Repositories
+// without a GitHub origin get PullRequestUnknown.
+func lookupPullRequest(st *gitops.RepoStatus, token string) error {
+	if st.Branch == "" || st.Branch == "HEAD" {
 		return nil
-	},
+	}
+	if mainBranch, err := gitops.DetectDefaultMainBranchIn(st.Path); err == nil && strings.TrimPrefix(mainBranch, "origin/") == st.Branch {
+		return nil
+	}
+	st.PullRequestState = gitops.PullRequestUnknown
+	originURL, err := gitops.RemoteURL(st.Path, "origin")
+	if err != nil {
+		return nil // no origin, nothing to ask
+	}
+	remote, err := forge.ParseRemoteURL(originURL)
+	if err != nil || !forge.IsGitHubHost(remote.Host) {
+		return nil // only GitHub is supported so far
+	}
+	number, err := forge.GitHubOpenPullRequest(interruptContext(), remote, st.Branch, token)
+	if err != nil {
+		return err
+	}
+	if number == 0 {
+		st.PullRequestState = gitops.PullRequestNone
+		return nil
+	}
+	st.PullRequest, st.PullRequestState = number, gitops.PullRequestOpen
+	return nil
+}
+
+// statusPullRequestText renders the PR column.
+func statusPullRequestText(st gitops.RepoStatus) string {
+	switch st.PullRequestState {
+	case gitops.PullRequestOpen:
+		return fmt.Sprintf("[PR #%d open]", st.PullRequest)
+	case gitops.PullRequestNone:
+		return "[No PR]"
+	case gitops.PullRequestUnknown:
+		return "?"
+	}
+	return "-"
+}
+
+// compareWithRemote recounts the ahead/behind numbers of st against the default
+// branch of remote (e.g. "upstream/main"). If that branch cannot be resolved
+// the @{u} numbers are kept and the reason is recorded in st.CompareError.
+func compareWithRemote(st *gitops.RepoStatus, remote string) {
+	ref, err := gitops.RemoteDefaultBranch(st.Path, remote)
+	if err == nil {
+		var ahead, behind int
+		if ahead, behind, err = gitops.AheadBehind(st.Path, ref); err == nil {
+			st.CompareRef = ref
+			st.Ahead, st.Behind = ahead, behind
+			st.Diverged = ahead > 0 && behind > 0
+			return
+		}
+	}
+	st.CompareError = err.Error()
+}
+
+// statusOriginText renders the origin URL column, shortened to maxOriginWidth.
+func statusOriginText(st gitops.RepoStatus) string {
+	switch {
+	case st.NoOrigin:
+		return "[No Origin]"
+	case st.OriginURL == "":
+		return "-"
+	}
+	url := []rune(st.OriginURL)
+	if len(url) <= maxOriginWidth {
+		return st.OriginURL
+	}
+	half := (maxOriginWidth - 3) / 2
+	return string(url[:half]) + "..." + string(url[len(url)-(maxOriginWidth-3-half):])
+}
+
+// statusLastCommitText renders the last commit column, e.g. "3 days ago: Fix login".
+func statusLastCommitText(st gitops.RepoStatus) string {
+	if st.LastCommitRelative == "" {
+		return "-"
+	}
+	subject := []rune(st.LastCommitSubject)
+	if len(subject) > maxSubjectWidth {
+		subject = append(subject[:maxSubjectWidth-3], []rune("...")...)
+	}
+	return st.LastCommitRelative + ": " + string(subject)
+}
+
+// statusStateText renders the working tree state of a repository, e.g. "Dirty"
+// or "Clean [REBASING]".
+func statusStateText(st gitops.RepoStatus) string {
+	if st.Bare {
+		return fmt.Sprintf("[Bare] %d branches", st.BranchCount)
+	}
+	state := "Clean"
+	if st.Dirty {
+		state = "Dirty"
+	}
+	switch st.State {
+	case gitops.StateMerging:
+		state += " [MERGING]"
+	case gitops.StateRebasing:
+		state += " [REBASING]"
+	}
+	if st.StashCount > 0 {
+		state += fmt.Sprintf(" [%d stashed]", st.StashCount)
+	}
+	return state
+}
+
+// statusUpstreamText renders how a repository compares to its upstream branch,
+// e.g. "Ahead 2, Behind 1" or "No Upstream".
+func statusUpstreamText(st gitops.RepoStatus) string {
+	text := statusAheadBehindText(st)
+	if st.CompareError != "" && st.HasUpstream {
+		text += " (@{u})" // --remote-name could not be used for this repository
+	}
+	return text
+}
+
+// statusAheadBehindText renders the ahead/behind state of st.
+func statusAheadBehindText(st gitops.RepoStatus) string {
+	switch {
+	case st.Bare:
+		return "-"
+	case st.CompareRef == "" && st.UpstreamError != "":
+		return "Error"
+	case st.CompareRef == "" && !st.HasUpstream:
+		return "No Upstream"
+	case st.Diverged:
+		return fmt.Sprintf("[Diverged] Ahead %d, Behind %d", st.Ahead, st.Behind)
+	case st.Ahead > 0:
+		return fmt.Sprintf("Ahead %d", st.Ahead)
+	case st.Behind > 0:
+		return fmt.Sprintf("Behind %d", st.Behind)
+	}
+	return "Up to date"
 }
 
 // --- init() function ---
 func init() {
 	rootCmd.AddCommand(statusCmd)
-	statusCmd.Flags().StringVarP(&statusDirectory, "directory", "D", "", "Directory to scan for Git repositories (defaults to current directory)")
+	addScanFlags(statusCmd, &statusScan)
+	addStdinFlag(statusCmd, &statusScan)
+	addDirNameOnlyFlag(statusCmd, &statusScan)
+	statusCmd.Flags().BoolVar(&onlyDirty, "only-dirty", false, "Only list repositories with uncommitted or untracked changes")
+	statusCmd.Flags().BoolVar(&onlyAhead, "only-ahead", false, "Only list repositories that are ahead of their upstream (combines with the other --only-* filters: all must match)")
+	statusCmd.Flags().BoolVar(&onlyBehind, "only-behind", false, "Only list repositories that are behind their upstream (combines with the other --only-* filters: all must match)")
+	statusCmd.Flags().StringSliceVar(&statusFailOn, "fail-on", nil, "Exit non-zero if any repository is in one of these states: dirty, ahead, behind, diverged, no-upstream, error")
+	statusCmd.Flags().StringVar(&statusRelativeTo, "relative-to", "", "Show repository paths relative to this directory instead of the scan root")
+	statusCmd.Flags().BoolVar(&statusNoUpstreamOK, "no-upstream-ok", false, "Treat branches without an upstream as intentional: they never match --fail-on")
+	statusCmd.Flags().BoolVar(&statusShowPR, "show-pr", false, "Show whether each feature branch has an open pull request on origin (GitHub)")
+	statusCmd.Flags().StringVar(&statusGitHubToken, "github-token", "", "GitHub API token for --show-pr (defaults to $GITHUB_TOKEN)")
+	statusCmd.Flags().BoolVar(&statusShowStash, "show-stash", false, "Mark repositories that have stash entries with [N stashed]")
+	statusCmd.Flags().BoolVar(&statusShowLastCommit, "show-last-commit", false, "Show the age and subject of each repository's HEAD commit")
+	statusCmd.Flags().BoolVar(&statusShowRemote, "show-remote", false, "Show each repository's origin URL, flagging repositories without an origin remote")
+	statusCmd.Flags().BoolVar(&statusGroupByRemote, "group-by-remote", false, "Group the output by the host of each repository's origin remote")
+	statusCmd.Flags().DurationVar(&statusWatch, "watch", 0, "Re-run the scan at this interval (e.g. 30s, 5m) until interrupted")
+	statusCmd.Flags().IntVarP(&statusJobs, "jobs", "j", 4, "Number of repositories to inspect in parallel")
+	statusCmd.Flags().BoolVar(&statusIgnoreUntracked, "ignore-untracked", false, "Do not count untracked files as changes when deciding whether a repository is dirty")
+	statusCmd.Flags().BoolVar(&statusAheadBehindOnly, "ahead-behind-only", false, "Skip the untracked file and submodule scan and report only branch and upstream state")
+	statusCmd.Flags().StringVar(&statusRemoteName, "remote-name", "", "Count ahead/behind against the default branch of this remote (e.g. upstream) instead of @{u}")
+	statusCmd.Flags().StringVar(&statusOutputDir, "output-dir", "", "Also write each repository's status as a JSON file named after it into this directory")
+	statusCmd.Flags().BoolVar(&statusSummaryOnly, "summary-only", false, "With --format json, print only the summary counts, without the per-repository array")
+	statusCmd.Flags().BoolVarP(&statusNull, "null", "0", false, "Print only the paths of the listed repositories, NUL-separated (for xargs -0)")
+	statusCmd.Flags().StringVarP(&statusFormat, "format", "f", "text", "Output format: 'text', 'json', 'csv', or a Go template executed per repository")
 }
-
-// --- Helper Functions ---
-// *** DO NOT DEFINE findGitRepos or runGitCommand here! ***
-// They should be defined only ONCE (e.g., in pkg/gitops/helpers.go)
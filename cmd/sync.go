@@ -1,151 +1,742 @@
 package cmd
 
 import (
-	// Required by runGitCommand (if called from here or package)
+	"errors"
 	"fmt"
-	"os" // Required by runGitCommand (if called from here or package)
-	"path/filepath"
+	"os"
 	"strings"
 
-	// We don't need 'strconv' in sync.go
-
+	"github.com/OmSingh2003/git-util/internal/output"
 	"github.com/OmSingh2003/git-util/pkg/gitops"
 	"github.com/spf13/cobra"
-	// NOTE: This code now relies on findGitRepos and runGitCommand
-	// being defined elsewhere in the 'cmd' package (e.g., in root.go)
-	// or being imported from a shared package if you refactor later.
 )
 
 // Variables to hold the flag values for the sync command
 var (
-	syncDirectory string
-	syncAction    string
+	syncScan       scanFlags
+	syncAction     string
+	syncStrict     bool
+	syncFormat     string
+	syncNoPrune    bool
+	syncStrategy   string
+	syncPreview    bool
+	syncAllRemotes bool
+
+	syncContinueOnDirty bool
+	syncAutostash       bool
+	syncJobs            int
+	syncLiveSummary     bool
+	syncSkipClean       bool
+	syncOutputDir       string
+	syncChangedOnly     bool
 )
 
+// pullStrategyArgs maps each --strategy value to the 'git pull' options it uses.
+var pullStrategyArgs = map[string][]string{
+	"ff-only": {"--ff-only"},
+	"merge":   {"--no-rebase"},
+	"rebase":  {"--rebase"},
+}
+
+// integrateArgs maps each --strategy value to the git command that applies the
+// upstream once it has been fetched: 'git pull' without its own fetch. The
+// upstream ("@{u}") is appended after any further options.
+var integrateArgs = map[string][]string{
+	"ff-only": {"merge", "--ff-only"},
+	"merge":   {"merge", "--no-edit"},
+	"rebase":  {"rebase"},
+}
+
+// validSyncActions lists the steps accepted by --action, in the order they are documented.
+var validSyncActions = []string{"fetch", "pull", "checkout-main"}
+
+// syncStepResult describes the outcome of running one sync step in one repository.
+type syncStepResult struct {
+	Output     string // combined output of the git command, shown on failure
+	Note       string // short extra information printed after OK, e.g. "checked out main"
+	SkipReason string // non-empty when the step (and the remaining ones) was skipped
+	UpToDate   bool   // pull --skip-clean: nothing to pull, 'git pull' was not run
+
+	// Set by the pull step when the branch and its upstream have diverged.
+	Diverged      bool
+	Ahead, Behind int
+
+	Incoming []string // pull --preview: commits that a pull would bring in, as "<sha> <subject>"
+}
+
+// syncResult is the outcome of syncing one repository.
+type syncResult struct {
+	Path         string   `json:"path"`
+	RelativePath string   `json:"relativePath"`
+	Status       string   `json:"status"` // "ok", "failed" or "skipped"
+	Notes        []string `json:"notes,omitempty"`
+	SkipReason   string   `json:"skipReason,omitempty"`
+	FailedAction string   `json:"failedAction,omitempty"`
+	Error        string   `json:"error,omitempty"`
+	Output       string   `json:"output,omitempty"`
+	Diverged     bool     `json:"diverged,omitempty"` // pull refused: branch and upstream have both moved
+	Ahead        int      `json:"ahead,omitempty"`
+	Behind       int      `json:"behind,omitempty"`
+	Incoming     []string `json:"incoming,omitempty"` // --preview only
+	UpToDate     bool     `json:"upToDate,omitempty"` // --skip-clean: already had every upstream commit
+
+	// Set with --changed-only: the commit HEAD (or, for fetch, @{u}) pointed at
+	// before and after syncing, and the number of commits it gained.
+	Before     string `json:"before,omitempty"`
+	After      string `json:"after,omitempty"`
+	NewCommits int    `json:"newCommits,omitempty"`
+}
+
+// syncSummary counts repositories by outcome.
+type syncSummary struct {
+	Repos     int `json:"repos"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	Skipped   int `json:"skipped"`
+	Cancelled int `json:"cancelled,omitempty"` // interrupted or not started because of Ctrl-C
+	UpToDate  int `json:"upToDate,omitempty"`  // succeeded without pulling (--skip-clean)
+	Changed   int `json:"changed,omitempty"`   // HEAD or @{u} moved (--changed-only)
+}
+
+// syncReport is the JSON document printed by 'sync --format json'.
+type syncReport struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	Action        string       `json:"action"`
+	Repos         []syncResult `json:"repos"`
+	Summary       syncSummary  `json:"summary"`
+}
+
 // syncCmd represents the sync command
 var syncCmd = &cobra.Command{
 	Use:   "sync",
 	Short: "Synchronize multiple Git repositories (fetch or pull).",
 	Long: `Scans a directory for Git repositories and runs 'git fetch --prune' (default)
-or 'git pull --ff-only' to synchronize them with their remotes.`,
+or 'git pull --ff-only' to synchronize them with their remotes.
+Pass --no-prune to fetch without removing stale remote-tracking branches, and
+--all-remotes to fetch every configured remote (e.g. origin and upstream)
+rather than only the default one.
+--strategy chooses how pull reconciles with the upstream branch: 'ff-only'
+(default), 'merge' or 'rebase'. With 'ff-only', a branch that has diverged from
+its upstream is reported as [Diverged: N ahead, M behind] without pulling. A merge or rebase that hits conflicts is
+aborted and reported, leaving the repository unchanged.
+When the upstream has already been fetched for these checks (with 'ff-only'
+or --skip-clean), the pull is completed with 'git merge' or 'git rebase'
+against @{u}, so each repository is fetched only once.
+
+--continue-on-dirty=false makes the pull action skip repositories with local
+changes, reported as [Skipped: dirty]. With --autostash they are pulled anyway:
+local changes are stashed before the pull and reapplied afterwards.
+
+--preview makes the pull action fetch and list the commits each repository
+would receive ('git log HEAD..@{u}') without pulling.
+
+--skip-clean makes the pull action fetch and compare each branch with its
+upstream first, and skip 'git pull' for repositories that are not behind,
+reported as [Up to date]. Repositories without an upstream are pulled as usual.
+
+The 'checkout-main' action switches each repository to its default branch
+(main or master, or the 'main' setting of its configuration), skipping
+repositories with a dirty working tree.
+
+Several actions can be combined with commas and run in order per repository,
+e.g. '--action checkout-main,pull'. If a step fails or is skipped, the remaining
+steps for that repository are not run.
+
+--dir-name-only shows just the directory name of each repository instead of its
+relative path, adding parent directories only where names clash.
+
+With --stdin the repositories are read from standard input, one path per
+line (e.g. from 'find' or 'fd'), instead of being discovered under --directory.
+
+Up to --jobs repositories are synced at the same time (default 1). Results are
+always printed in discovery order, each repository's lines together. On a
+terminal a progress line shows how many repositories are complete and which
+ones are being synced. --live-summary replaces it with a single line counting
+repositories done, failed and in progress, and prints the per-repository
+results together at the end; it has no effect when the output is not a terminal.
+
+Errors are collected and listed on stderr after the summary, in an
+'--- Errors (N) ---' section with each repository and its message (and git's
+output). --verbose also prints each one as it happens.
+
+--changed-only records the commit each repository's HEAD points at before and
+after syncing (its upstream @{u} when only fetching) and lists just the
+repositories that moved in the summary, with the number of new commits each
+received, e.g. 'api : 3 new commits'. Repositories without an upstream are not
+tracked for a fetch.
+
+--output-dir writes each repository's result as its own JSON file into the
+given directory, named after the repository's displayed path (e.g.
+team/api.json), creating directories as needed.
+
+With --strict the command exits with a non-zero status if any repository
+failed to sync, after printing the summary.
+
+--format selects the output: 'text' (default), 'json', or a Go template that is
+executed once per repository. Template fields: Path, RelativePath, Status
+(ok, failed or skipped), Notes, SkipReason, FailedAction, Error, Output,
+Diverged, Ahead, Behind, Incoming, UpToDate, Before, After, NewCommits.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// --- Determine Target Directory ---
-		targetDir := syncDirectory
-		if targetDir == "" {
-			var err error
-			targetDir, err = os.Getwd()
-			if err != nil {
-				return fmt.Errorf("failed to get current directory: %w", err)
-			}
+		format, err := parseOutputFormat(syncFormat, syncResult{})
+		if err != nil {
+			return err
+		}
+		if err := validateJobs(syncJobs); err != nil {
+			return err
 		}
-		// Get absolute path for consistency
-		var err error
-		targetDir, err = filepath.Abs(targetDir)
+
+		// --- Determine Target Directory ---
+		targetDir, err := resolveTargetDir(syncScan.directory)
 		if err != nil {
-			return fmt.Errorf("failed to get absolute path for target directory: %w", err)
+			return err
+		}
+
+		if err := loadGlobalConfig(); err != nil {
+			return err
 		}
 
 		// --- Validate Action ---
-		action := strings.ToLower(syncAction)
-		if action != "fetch" && action != "pull" {
-			return fmt.Errorf("invalid action '%s': must be 'fetch' or 'pull'", syncAction)
+		actions, err := parseSyncActions(syncAction)
+		if err != nil {
+			return err
+		}
+		syncStrategy = strings.ToLower(syncStrategy)
+		if _, ok := pullStrategyArgs[syncStrategy]; !ok {
+			return fmt.Errorf("invalid strategy '%s': must be 'ff-only', 'merge' or 'rebase'", syncStrategy)
+		}
+		actionLabel := strings.Join(actions, ",")
+		if format.isText() {
+			previewNote := ""
+			if syncPreview {
+				previewNote = ", preview only"
+			}
+			fmt.Printf("Scanning directory: %s (Action: %s%s)\n", targetDir, actionLabel, previewNote)
 		}
-		fmt.Printf("Scanning directory: %s (Action: %s)\n", targetDir, action)
 
 		// --- Find Repositories ---
-		// *** This call was missing in the code you pasted ***
-		// Call the helper function (defined elsewhere in cmd package, e.g., root.go)
-		repos, err := gitops.FindGitRepos(targetDir)
+		repos, err := syncScan.findRepos(targetDir)
 		if err != nil {
-			return fmt.Errorf("error finding repositories: %w", err)
+			return err
 		}
 
-		if len(repos) == 0 {
+		if len(repos) == 0 && format.isText() {
 			fmt.Println("No Git repositories found in the specified directory.")
 			return nil
 		}
 
-		fmt.Printf("\n--- Synchronizing Repositories ---\n")
+		if format.isText() {
+			fmt.Printf("\n--- Synchronizing Repositories ---\n")
+		}
 
 		// --- Calculate Max Path Length for Formatting ---
-		maxLen := 0
-		for _, repoPath := range repos {
-			relPath, _ := filepath.Rel(targetDir, repoPath)
-			if relPath == "." {
-				relPath = filepath.Base(targetDir)
-			}
-			if len(relPath) > maxLen {
-				maxLen = len(relPath)
+		names := syncScan.displayNames(targetDir, repos)
+		maxLen := maxNameLen(names)
+
+		// --- Process Repositories in Parallel ---
+		report := syncReport{SchemaVersion: jsonSchemaVersion, Action: actionLabel, Repos: make([]syncResult, len(repos))}
+		var prog *progress
+		var out *output.Ordered
+		if format.isText() {
+			prog = newProgress(os.Stdout, len(repos))
+			out = output.NewOrdered(prog.writer(os.Stdout), prog.writer(os.Stderr), len(repos))
+			if syncLiveSummary && prog.tty {
+				prog.summaryLabel = "Syncing"
+				out.Hold() // the per-repository list follows once everything is done
 			}
+		} else {
+			out = output.NewOrdered(os.Stdout, os.Stderr, len(repos))
 		}
+		repoErrs := newRepoErrors(len(repos))
+		started := runParallel(syncJobs, len(repos), func(i int) {
+			relPath := names[repos[i]]
+			task := out.Task(i)
 
-		// --- Process Each Repository ---
-		successCount := 0
-		failCount := 0
-		for _, repoPath := range repos {
-			relPath, _ := filepath.Rel(targetDir, repoPath)
-			if relPath == "." {
-				relPath = filepath.Base(targetDir)
+			if prog != nil {
+				prog.start(relPath)
 			}
 
-			fmt.Printf("%-*s : Syncing (%s)... ", maxLen, relPath, action)
-
-			var gitArgs []string
-			if action == "fetch" {
-				gitArgs = []string{"fetch", "--prune"}
-			} else { // action == "pull"
-				gitArgs = []string{"pull", "--ff-only"}
+			result := syncRepo(repos[i], actions)
+			result.RelativePath = relPath
+			if result.Status == "failed" && interrupted() {
+				result.Status = "cancelled"
 			}
+			report.Repos[i] = result
 
-			// Prepend -C <path> to run in the correct directory
-			gitFullArgs := append([]string{"-C", repoPath}, gitArgs...)
-
-			// *** This call was missing in the code you pasted ***
-			// Execute the command using the helper (defined elsewhere in cmd package, e.g., root.go)
-			output, err := gitops.RunGitCommand(gitFullArgs...) // output is now assigned
-
-			// Check for errors after executing the command
+			if prog != nil {
+				task.Printf("%-*s : Syncing (%s)... %s\n", maxLen, relPath, actionLabel, syncResultText(result))
+				for _, commit := range result.Incoming {
+					task.Printf("    %s\n", commit)
+				}
+			}
+			if result.Status == "failed" {
+				// Record a concise error, including output from the command
+				message := fmt.Sprintf("(%s) %s", result.FailedAction, result.Error)
+				if result.Output != "" {
+					message += "\nOutput: " + result.Output
+				}
+				repoErrs.add(task, i, relPath, "%s", message)
+			}
+			if prog != nil {
+				prog.complete(relPath, result.Status == "failed")
+			}
+			task.Done()
+		})
+		if prog != nil {
+			prog.stop()
+			out.Release()
+		}
+		for i := started; i < len(repos); i++ {
+			report.Repos[i] = syncResult{Path: repos[i], RelativePath: names[repos[i]], Status: "cancelled", Error: "not started"}
+		}
+		for _, result := range report.Repos {
+			report.Summary.add(result)
+		}
+		// --- Write Per-Repository Reports ---
+		if syncOutputDir != "" {
+			dir, err := expandPath(syncOutputDir)
 			if err != nil {
-				fmt.Printf("FAILED\n")
-				// Print concise error, including output from the command
-				fmt.Fprintf(os.Stderr, "  Error for %s: %v\n  Output: %s\n", relPath, err, output) // Using output here
-				failCount++
+				return err
+			}
+			for _, result := range report.Repos {
+				if err := writeRepoReport(dir, result.Path, result.RelativePath, result); err != nil {
+					return err
+				}
+			}
+		}
+		setReportSummary("%d repositories: %d synced, %d failed, %d skipped",
+			report.Summary.Repos, report.Summary.Succeeded, report.Summary.Failed, report.Summary.Skipped)
+
+		switch format.kind {
+		case "json":
+			if err := writeJSON(os.Stdout, report); err != nil {
+				return err
+			}
+		case "template":
+			for _, result := range report.Repos {
+				if err := format.executeTemplate(os.Stdout, result); err != nil {
+					return err
+				}
+			}
+		default:
+			// Print summary
+			fmt.Printf("\n--- Summary ---\n")
+			if interrupted() {
+				fmt.Printf("Action '%s' interrupted.\n", actionLabel)
 			} else {
-				fmt.Printf("OK\n")
-				// Optional: Print output even on success if needed (can be noisy)
-				// if output != "" { fmt.Printf("  Output: %s\n", output) }
-				successCount++
+				fmt.Printf("Action '%s' completed.\n", actionLabel)
+			}
+			fmt.Printf("  Successfully synced: %d\n", report.Summary.Succeeded)
+			fmt.Printf("  Failed to sync:    %d\n", report.Summary.Failed)
+			if report.Summary.UpToDate > 0 {
+				fmt.Printf("  Already up to date: %d\n", report.Summary.UpToDate)
 			}
-		} // End loop
+			if report.Summary.Skipped > 0 {
+				fmt.Printf("  Skipped:           %d\n", report.Summary.Skipped)
+			}
+			if report.Summary.Cancelled > 0 {
+				fmt.Printf("  Cancelled:         %d (interrupted: %d, not started: %d)\n",
+					report.Summary.Cancelled, report.Summary.Cancelled-(len(repos)-started), len(repos)-started)
+			}
+			if syncOutputDir != "" {
+				fmt.Printf("  Reports written to: %s\n", syncOutputDir)
+			}
+			if syncChangedOnly {
+				printChangedRepos(report.Repos, maxLen)
+			}
+		}
 
-		// Print summary
-		fmt.Printf("\n--- Summary ---\n")
-		fmt.Printf("Action '%s' completed.\n", action)
-		fmt.Printf("  Successfully synced: %d\n", successCount)
-		fmt.Printf("  Failed to sync:    %d\n", failCount)
+		repoErrs.write(os.Stderr)
 
+		if interrupted() {
+			cmd.SilenceUsage = true
+			return errors.New("sync interrupted")
+		}
+
+		if syncStrict && report.Summary.Failed > 0 {
+			cmd.SilenceUsage = true // the failure is already reported above; usage would only add noise
+			return fmt.Errorf("%d of %d repositories failed to sync", report.Summary.Failed, len(repos))
+		}
 		return nil
 	},
 }
 
+// syncRepo runs every action in order in the repository at repoPath, stopping
+// at the first failure or skip. With --changed-only it also records how the
+// tracked commit (see syncTipRef) moved.
+func syncRepo(repoPath string, actions []string) syncResult {
+	if !syncChangedOnly {
+		return runSyncActions(repoPath, actions)
+	}
+	tip := syncTipRef(actions)
+	before, err := gitops.ResolveCommit(repoPath, tip)
+	result := runSyncActions(repoPath, actions)
+	if err != nil {
+		return result // nothing to compare against, e.g. no upstream
+	}
+	result.Before = before
+	if result.After, err = gitops.ResolveCommit(repoPath, tip); err != nil || result.After == before {
+		return result
+	}
+	result.NewCommits, _ = gitops.CountCommits(repoPath, before, result.After)
+	return result
+}
+
+// syncTipRef returns the ref whose movement --changed-only reports: HEAD when
+// the actions update the working tree, or the upstream of the current branch
+// when they only fetch.
+func syncTipRef(actions []string) string {
+	for _, action := range actions {
+		if action != "fetch" {
+			return "HEAD"
+		}
+	}
+	return "@{u}"
+}
+
+// changed reports whether --changed-only saw the tracked commit move.
+func (r syncResult) changed() bool {
+	return r.Before != "" && r.After != "" && r.After != r.Before
+}
+
+// printChangedRepos lists the repositories whose tracked commit moved, with
+// the number of new commits, as part of the text summary.
+func printChangedRepos(results []syncResult, maxLen int) {
+	var changed []syncResult
+	for _, result := range results {
+		if result.changed() {
+			changed = append(changed, result)
+		}
+	}
+	if len(changed) == 0 {
+		fmt.Printf("\nNo repositories received new commits.\n")
+		return
+	}
+	fmt.Printf("\n--- Updated Repositories (%d) ---\n", len(changed))
+	for _, result := range changed {
+		switch result.NewCommits {
+		case 0:
+			// Moved without gaining commits, e.g. the upstream was rewound.
+			fmt.Printf("%-*s : moved to %.7s (no new commits)\n", maxLen, result.RelativePath, result.After)
+		case 1:
+			fmt.Printf("%-*s : 1 new commit\n", maxLen, result.RelativePath)
+		default:
+			fmt.Printf("%-*s : %d new commits\n", maxLen, result.RelativePath, result.NewCommits)
+		}
+	}
+}
+
+// runSyncActions runs every action in order in the repository at repoPath,
+// stopping at the first failure or skip.
+func runSyncActions(repoPath string, actions []string) syncResult {
+	result := syncResult{Path: repoPath, Status: "ok"}
+	for _, action := range actions {
+		step, err := runSyncStep(repoPath, action)
+		if err != nil {
+			result.Status = "failed"
+			result.FailedAction = action
+			result.Error = err.Error()
+			result.Output = step.Output
+			result.Diverged, result.Ahead, result.Behind = step.Diverged, step.Ahead, step.Behind
+			return result
+		}
+		if step.SkipReason != "" {
+			result.Status = "skipped"
+			result.SkipReason = step.SkipReason
+			return result
+		}
+		if step.UpToDate {
+			result.UpToDate = true
+		}
+		if step.Note != "" {
+			result.Notes = append(result.Notes, step.Note)
+		}
+		result.Incoming = append(result.Incoming, step.Incoming...)
+	}
+	return result
+}
+
+// syncResultText renders the text printed after "Syncing (...)... " for result.
+func syncResultText(result syncResult) string {
+	switch result.Status {
+	case "failed":
+		if result.Diverged {
+			return fmt.Sprintf("FAILED [Diverged: %d ahead, %d behind]", result.Ahead, result.Behind)
+		}
+		return "FAILED"
+	case "skipped":
+		return fmt.Sprintf("[Skipped: %s]", result.SkipReason)
+	case "cancelled":
+		return "[Cancelled]"
+	}
+	if result.UpToDate {
+		if len(result.Notes) > 0 {
+			return fmt.Sprintf("[Up to date] (%s)", strings.Join(result.Notes, "; "))
+		}
+		return "[Up to date]"
+	}
+	if len(result.Notes) > 0 {
+		return fmt.Sprintf("OK (%s)", strings.Join(result.Notes, "; "))
+	}
+	return "OK"
+}
+
+// add counts result in the summary.
+func (s *syncSummary) add(result syncResult) {
+	s.Repos++
+	switch result.Status {
+	case "failed":
+		s.Failed++
+	case "skipped":
+		s.Skipped++
+	case "cancelled":
+		s.Cancelled++
+	default:
+		s.Succeeded++
+		if result.UpToDate {
+			s.UpToDate++
+		}
+	}
+	if result.changed() {
+		s.Changed++
+	}
+}
+
+// explainPullFailure turns the common reasons for a failed pull into actionable
+// errors. An interrupted merge or rebase is aborted so the repository is left as
+// it was before the pull.
+func explainPullFailure(repoPath string, err error) error {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "Not possible to fast-forward"):
+		return fmt.Errorf("diverged from upstream, cannot fast-forward; retry with --strategy merge or --strategy rebase")
+	case syncStrategy == "rebase" && (strings.Contains(msg, "CONFLICT") || strings.Contains(msg, "could not apply")):
+		gitops.RunGitCommand("-C", repoPath, "rebase", "--abort")
+		return fmt.Errorf("rebase onto upstream hit conflicts and was aborted; rebase manually")
+	case syncStrategy == "merge" && strings.Contains(msg, "CONFLICT"):
+		gitops.RunGitCommand("-C", repoPath, "merge", "--abort")
+		return fmt.Errorf("merge with upstream hit conflicts and was aborted; merge manually")
+	}
+	return err
+}
+
+// checkPullUpstream fetches the upstream of the current branch before a pull
+// and reports whether it did. With --skip-clean a branch that is not behind is
+// marked up to date, and with --strategy ff-only a branch whose upstream has
+// diverged (both sides have new commits) is reported as an error, with the
+// counts recorded in result. A branch without an upstream is not fetched and
+// left for 'git pull' to report.
+func checkPullUpstream(repoPath string, result *syncStepResult) (bool, error) {
+	if has, err := gitops.HasUpstream(repoPath); err != nil || !has {
+		return false, nil
+	}
+	output, err := gitops.RunGitCommand("-C", repoPath, "fetch")
+	if err != nil {
+		result.Output = output
+		return false, err
+	}
+	ahead, behind, err := gitops.AheadBehind(repoPath, "@{u}")
+	if err != nil {
+		return true, nil
+	}
+	if syncSkipClean && behind == 0 {
+		result.UpToDate = true
+		return true, nil
+	}
+	if syncStrategy != "ff-only" || ahead == 0 || behind == 0 {
+		return true, nil
+	}
+	result.Diverged, result.Ahead, result.Behind = true, ahead, behind
+	return true, fmt.Errorf("local branch and upstream have diverged (%d ahead, %d behind); retry with --strategy rebase to replay local commits or --strategy merge to merge, or reconcile manually", ahead, behind)
+}
+
+// previewPull fetches the upstream of the current branch and lists the commits
+// a pull would bring in, without changing the working tree or local branches.
+func previewPull(repoPath string) (syncStepResult, error) {
+	var result syncStepResult
+	if has, err := gitops.HasUpstream(repoPath); err != nil {
+		return result, err
+	} else if !has {
+		result.SkipReason = "no upstream"
+		return result, nil
+	}
+	output, err := gitops.RunGitCommand("-C", repoPath, "fetch")
+	if err != nil {
+		result.Output = output
+		return result, err
+	}
+	ahead, behind, err := gitops.AheadBehind(repoPath, "@{u}")
+	if err != nil {
+		return result, err
+	}
+	if behind == 0 {
+		result.Note = "up to date"
+	} else {
+		log, err := gitops.RunGitCommand("-C", repoPath, "log", "--format=%h %s", "HEAD..@{u}")
+		if err != nil {
+			return result, err
+		}
+		result.Incoming = strings.Split(log, "\n")
+		result.Note = fmt.Sprintf("%d incoming", behind)
+	}
+	if ahead > 0 {
+		result.Note += fmt.Sprintf(", %d local not on upstream", ahead)
+	}
+	return result, nil
+}
+
+// parseSyncActions splits a comma-separated --action value and validates each step.
+func parseSyncActions(value string) ([]string, error) {
+	var actions []string
+	for _, part := range strings.Split(value, ",") {
+		action := strings.ToLower(strings.TrimSpace(part))
+		if action == "" {
+			continue
+		}
+		valid := false
+		for _, v := range validSyncActions {
+			if action == v {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("invalid action '%s': must be one of %s", part, strings.Join(validSyncActions, ", "))
+		}
+		actions = append(actions, action)
+	}
+	if len(actions) == 0 {
+		return nil, fmt.Errorf("no action specified: must be one of %s", strings.Join(validSyncActions, ", "))
+	}
+	return actions, nil
+}
+
+// runSyncStep runs a single sync action in the repository at repoPath.
+func runSyncStep(repoPath, action string) (syncStepResult, error) {
+	var result syncStepResult
+	if action != "fetch" && gitops.IsBareRepo(repoPath) {
+		result.SkipReason = "bare repository" // nothing is checked out to update
+		return result, nil
+	}
+	switch action {
+	case "fetch":
+		gitArgs := []string{"-C", repoPath, "fetch"}
+		if syncAllRemotes {
+			gitArgs = append(gitArgs, "--all")
+		}
+		if !syncNoPrune {
+			gitArgs = append(gitArgs, "--prune")
+		}
+		output, err := gitops.RunGitCommand(gitArgs...)
+		result.Output = output
+		if err == nil && syncAllRemotes {
+			if remotes, err := gitops.Remotes(repoPath); err == nil {
+				result.Note = fmt.Sprintf("fetched %d remotes", len(remotes))
+				if len(remotes) == 1 {
+					result.Note = "fetched 1 remote"
+				}
+			}
+		}
+		return result, err
+
+	case "pull":
+		if syncPreview {
+			return previewPull(repoPath)
+		}
+		if !syncContinueOnDirty && !syncAutostash {
+			dirty, err := gitops.IsDirty(repoPath)
+			if err != nil {
+				return result, fmt.Errorf("failed to check working tree: %w", err)
+			}
+			if dirty {
+				result.SkipReason = "dirty"
+				return result, nil
+			}
+		}
+		fetched := false
+		if syncStrategy == "ff-only" || syncSkipClean {
+			var err error
+			if fetched, err = checkPullUpstream(repoPath, &result); err != nil || result.UpToDate {
+				return result, err
+			}
+		}
+		// Once fetched, running 'git pull' would fetch a second time.
+		gitArgs := append([]string{"-C", repoPath, "pull"}, pullStrategyArgs[syncStrategy]...)
+		if fetched {
+			gitArgs = append([]string{"-C", repoPath}, integrateArgs[syncStrategy]...)
+		}
+		if syncAutostash {
+			gitArgs = append(gitArgs, "--autostash")
+		}
+		if fetched {
+			gitArgs = append(gitArgs, "@{u}")
+		}
+		output, err := gitops.RunGitCommand(gitArgs...)
+		result.Output = output
+		if err != nil {
+			return result, explainPullFailure(repoPath, err)
+		}
+		return result, nil
+
+	case "checkout-main":
+		dirty, err := gitops.IsDirty(repoPath)
+		if err != nil {
+			return result, fmt.Errorf("failed to check working tree: %w", err)
+		}
+		if dirty {
+			result.SkipReason = "dirty"
+			return result, nil
+		}
+		cfg, err := repoConfig(repoPath)
+		if err != nil {
+			return result, err
+		}
+		mainBranch := cfg.Main
+		if mainBranch == "" {
+			if mainBranch, err = gitops.DetectDefaultMainBranchIn(repoPath); err != nil {
+				return result, err
+			}
+		}
+		current, err := gitops.CurrentBranch(repoPath)
+		if err != nil {
+			return result, err
+		}
+		// A remote-only default ("origin/trunk") is checked out by its short name,
+		// letting git create the local tracking branch.
+		mainBranch = strings.TrimPrefix(mainBranch, "origin/")
+		if current == mainBranch {
+			result.Note = "already on " + mainBranch
+			return result, nil
+		}
+		output, err := gitops.RunGitCommand("-C", repoPath, "checkout", mainBranch)
+		result.Output = output
+		if err == nil {
+			result.Note = "checked out " + mainBranch
+		}
+		return result, err
+	}
+	return result, fmt.Errorf("unknown action '%s'", action)
+}
+
 func init() {
 	// Register syncCmd with the root command
 	rootCmd.AddCommand(syncCmd)
 
 	// Define flags specific to the sync command
-	syncCmd.Flags().StringVarP(&syncDirectory, "directory", "D", "", "Directory to scan for Git repositories (defaults to current directory)")
-	syncCmd.Flags().StringVarP(&syncAction, "action", "a", "fetch", "Sync action to perform: 'fetch' (default) or 'pull'")
+	addScanFlags(syncCmd, &syncScan)
+	addStdinFlag(syncCmd, &syncScan)
+	addDirNameOnlyFlag(syncCmd, &syncScan)
+	syncCmd.Flags().StringVarP(&syncAction, "action", "a", "fetch", "Sync action(s) to perform, comma-separated: 'fetch' (default), 'pull' or 'checkout-main'")
+	syncCmd.Flags().StringVarP(&syncFormat, "format", "f", "text", "Output format: 'text', 'json', or a Go template executed per repository")
+	syncCmd.Flags().StringVar(&syncStrategy, "strategy", "ff-only", "How the pull action reconciles with upstream: 'ff-only', 'merge' or 'rebase'")
+	syncCmd.Flags().BoolVar(&syncAllRemotes, "all-remotes", false, "Make the fetch action fetch every remote ('git fetch --all') instead of only the default one")
+	syncCmd.Flags().BoolVar(&syncNoPrune, "no-prune", false, "Fetch without --prune, keeping remote-tracking branches that no longer exist on the remote")
+	syncCmd.Flags().BoolVar(&syncPreview, "preview", false, "For the pull action, fetch and list the incoming commits without pulling")
+	syncCmd.Flags().BoolVar(&syncSkipClean, "skip-clean", false, "For the pull action, skip 'git pull' in repositories that are not behind their upstream")
+	syncCmd.Flags().BoolVar(&syncContinueOnDirty, "continue-on-dirty", true, "Pull into repositories with local changes; set to false to skip them")
+	syncCmd.Flags().BoolVar(&syncAutostash, "autostash", false, "Stash local changes before pulling and reapply them afterwards ('git pull --autostash')")
+	syncCmd.Flags().IntVarP(&syncJobs, "jobs", "j", 1, "Number of repositories to sync in parallel")
+	syncCmd.Flags().BoolVar(&syncLiveSummary, "live-summary", false, "On a terminal, show a live done/failed/in-progress count and list the results at the end")
+	syncCmd.Flags().BoolVar(&syncChangedOnly, "changed-only", false, "Record HEAD (or @{u} for fetch) before and after, and list only the repositories that received new commits in the summary")
+	syncCmd.Flags().StringVar(&syncOutputDir, "output-dir", "", "Also write each repository's result as a JSON file named after it into this directory")
+	syncCmd.Flags().BoolVar(&syncStrict, "strict", false, "Exit with a non-zero status if any repository fails to sync")
 }
-
-// --- Helper Functions ---
-// *** DELETE THE DEFINITIONS of findGitRepos and runGitCommand from this file! ***
-// They should only be defined ONCE in your cmd package (e.g., in cmd/root.go)
-// Leaving them defined here WILL cause the 'redeclared' build errors again.
-
-/* DELETE THIS FUNCTION DEFINITION from sync.go:
-func findGitRepos(rootDir string) ([]string, error) { ... }
-*/
-
-/* DELETE THIS FUNCTION DEFINITION from sync.go:
-func runGitCommand(args ...string) (string, error) { ... }
-*/
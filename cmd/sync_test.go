@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/OmSingh2003/git-util/internal/gitopstest"
+	"github.com/OmSingh2003/git-util/pkg/gitops"
+)
+
+// setSyncFlags sets the sync flag variables to their command-line defaults,
+// then applies change, and restores the previous values when the test ends.
+func setSyncFlags(t *testing.T, change func()) {
+	t.Helper()
+	strategy, skipClean, preview := syncStrategy, syncSkipClean, syncPreview
+	continueOnDirty, autostash, noPrune, allRemotes := syncContinueOnDirty, syncAutostash, syncNoPrune, syncAllRemotes
+	t.Cleanup(func() {
+		syncStrategy, syncSkipClean, syncPreview = strategy, skipClean, preview
+		syncContinueOnDirty, syncAutostash, syncNoPrune, syncAllRemotes = continueOnDirty, autostash, noPrune, allRemotes
+	})
+	syncStrategy, syncSkipClean, syncPreview = "ff-only", false, false
+	syncContinueOnDirty, syncAutostash, syncNoPrune, syncAllRemotes = true, false, false, false
+	if change != nil {
+		change()
+	}
+}
+
+// countCalls returns how many recorded invocations ran the git subcommand.
+func countCalls(fake *gitopstest.FakeRunner, subcommand string) int {
+	n := 0
+	for _, call := range fake.Calls() {
+		if len(call) > 2 && call[2] == subcommand { // calls start with "-C <repo>"
+			n++
+		}
+	}
+	return n
+}
+
+func TestRunSyncStep(t *testing.T) {
+	noUpstream := errors.New("command 'git rev-list' failed: exit status 128\nStderr: fatal: no upstream configured for branch 'topic'")
+	type call struct {
+		output string
+		err    error
+		args   []string // after "-C <repo>"
+	}
+	aheadBehind := func(output string, err error) call {
+		return call{output: output, err: err, args: []string{"rev-list", "--left-right", "--count", "HEAD...@{u}"}}
+	}
+	tests := []struct {
+		name    string
+		action  string
+		flags   func()
+		calls   []call
+		wantErr string // substring; "" for success
+		check   func(t *testing.T, step syncStepResult, fake *gitopstest.FakeRunner)
+	}{
+		{
+			name:   "fetch prunes by default",
+			action: "fetch",
+			calls:  []call{{args: []string{"fetch", "--prune"}}},
+		},
+		{
+			name:   "fetch without prune",
+			action: "fetch",
+			flags:  func() { syncNoPrune = true },
+			calls:  []call{{args: []string{"fetch"}}},
+		},
+		{
+			name:    "fetch failure keeps output",
+			action:  "fetch",
+			calls:   []call{{output: "remote hung up", err: errors.New("exit status 128"), args: []string{"fetch", "--prune"}}},
+			wantErr: "exit status 128",
+			check: func(t *testing.T, step syncStepResult, _ *gitopstest.FakeRunner) {
+				if step.Output != "remote hung up" {
+					t.Errorf("Output = %q, want the git output", step.Output)
+				}
+			},
+		},
+		{
+			name:   "ff-only pull fetches once and fast-forwards",
+			action: "pull",
+			calls: []call{
+				aheadBehind("0\t2", nil),
+				{args: []string{"fetch"}},
+				{args: []string{"merge", "--ff-only", "@{u}"}},
+			},
+			check: func(t *testing.T, _ syncStepResult, fake *gitopstest.FakeRunner) {
+				if n := countCalls(fake, "fetch"); n != 1 {
+					t.Errorf("fetched %d times, want 1", n)
+				}
+				if n := countCalls(fake, "pull"); n != 0 {
+					t.Errorf("ran 'git pull' %d times after fetching, want 0", n)
+				}
+			},
+		},
+		{
+			name:   "ff-only pull refuses a diverged branch",
+			action: "pull",
+			calls: []call{
+				aheadBehind("1\t2", nil),
+				{args: []string{"fetch"}},
+			},
+			wantErr: "diverged",
+			check: func(t *testing.T, step syncStepResult, fake *gitopstest.FakeRunner) {
+				if !step.Diverged || step.Ahead != 1 || step.Behind != 2 {
+					t.Errorf("step = %+v, want Diverged with 1 ahead, 2 behind", step)
+				}
+				if n := countCalls(fake, "merge"); n != 0 {
+					t.Errorf("merged %d times, want 0", n)
+				}
+			},
+		},
+		{
+			name:   "ff-only pull without upstream is left to git pull",
+			action: "pull",
+			calls: []call{
+				aheadBehind("", noUpstream),
+				{args: []string{"pull", "--ff-only"}},
+			},
+			check: func(t *testing.T, _ syncStepResult, fake *gitopstest.FakeRunner) {
+				if n := countCalls(fake, "fetch"); n != 0 {
+					t.Errorf("fetched %d times before the pull, want 0", n)
+				}
+			},
+		},
+		{
+			name:   "skip-clean leaves up-to-date branches alone",
+			action: "pull",
+			flags:  func() { syncSkipClean = true },
+			calls: []call{
+				aheadBehind("0\t0", nil),
+				{args: []string{"fetch"}},
+			},
+			check: func(t *testing.T, step syncStepResult, fake *gitopstest.FakeRunner) {
+				if !step.UpToDate {
+					t.Errorf("UpToDate = false, want true")
+				}
+				if n := countCalls(fake, "merge"); n != 0 {
+					t.Errorf("merged %d times, want 0", n)
+				}
+			},
+		},
+		{
+			name:   "skip-clean rebase with autostash after the fetch",
+			action: "pull",
+			flags:  func() { syncSkipClean, syncStrategy, syncAutostash = true, "rebase", true },
+			calls: []call{
+				aheadBehind("1\t3", nil),
+				{args: []string{"fetch"}},
+				{args: []string{"rebase", "--autostash", "@{u}"}},
+			},
+		},
+		{
+			name:   "rebase pull without checks runs git pull",
+			action: "pull",
+			flags:  func() { syncStrategy = "rebase" },
+			calls:  []call{{args: []string{"pull", "--rebase"}}},
+		},
+		{
+			name:   "dirty repository skipped",
+			action: "pull",
+			flags:  func() { syncContinueOnDirty = false },
+			calls:  []call{{output: " M main.go", args: []string{"status", "--porcelain=v1"}}},
+			check: func(t *testing.T, step syncStepResult, _ *gitopstest.FakeRunner) {
+				if step.SkipReason != "dirty" {
+					t.Errorf("SkipReason = %q, want dirty", step.SkipReason)
+				}
+			},
+		},
+		{
+			name:   "checkout-main switches branch",
+			action: "checkout-main",
+			calls: []call{
+				{args: []string{"status", "--porcelain=v1"}},
+				{args: []string{"show-ref", "--verify", "--quiet", "refs/heads/main"}},
+				{output: "feature", args: []string{"rev-parse", "--abbrev-ref", "HEAD"}},
+				{args: []string{"checkout", "main"}},
+			},
+			check: func(t *testing.T, step syncStepResult, _ *gitopstest.FakeRunner) {
+				if step.Note != "checked out main" {
+					t.Errorf("Note = %q, want %q", step.Note, "checked out main")
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setSyncFlags(t, tt.flags)
+			repo := t.TempDir()
+			fake := gitopstest.NewFakeRunner()
+			for _, c := range tt.calls {
+				fake.On(c.output, c.err, append([]string{"-C", repo}, c.args...)...)
+			}
+			defer gitops.SetRunner(fake)()
+
+			step, err := runSyncStep(repo, tt.action)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("runSyncStep() error = %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("runSyncStep() error = %v, want one containing %q", err, tt.wantErr)
+			}
+			for _, c := range tt.calls {
+				if !fake.Called(append([]string{"-C", repo}, c.args...)...) {
+					t.Errorf("expected 'git %s' to be run", strings.Join(c.args, " "))
+				}
+			}
+			if tt.check != nil {
+				tt.check(t, step, fake)
+			}
+		})
+	}
+}
+
+func TestSyncRepoStopsAtFirstFailure(t *testing.T) {
+	setSyncFlags(t, nil)
+	repo := t.TempDir()
+	fake := gitopstest.NewFakeRunner().
+		On("", errors.New("exit status 1"), "-C", repo, "fetch", "--prune")
+	defer gitops.SetRunner(fake)()
+
+	result := syncRepo(repo, []string{"fetch", "pull"})
+	if result.Status != "failed" || result.FailedAction != "fetch" {
+		t.Errorf("syncRepo() = %+v, want failed at fetch", result)
+	}
+	if n := len(fake.Calls()); n != 1 {
+		t.Errorf("ran %d git commands, want 1 (pull must not run after a failed fetch)", n)
+	}
+}
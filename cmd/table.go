@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// ansiEscape matches ANSI SGR sequences such as "\033[31m", which take up no
+// columns on screen.
+var ansiEscape = regexp.MustCompile("\033\\[[0-9;]*m")
+
+// displayWidth returns the number of terminal columns s occupies, ignoring ANSI
+// color codes. Unlike text/tabwriter, which counts bytes, this keeps colored
+// cells aligned.
+func displayWidth(s string) int {
+	return utf8.RuneCountInString(ansiEscape.ReplaceAllString(s, ""))
+}
+
+// table collects rows of cells and prints them with every column padded to
+// its widest cell.
+type table struct {
+	rows [][]string
+}
+
+// addRow appends one row. Rows may have different numbers of cells.
+func (t *table) addRow(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+// write prints the rows, each prefixed with indent and with columns separated
+// by two spaces. The last cell of a row is not padded, so lines have no
+// trailing whitespace.
+func (t *table) write(w io.Writer, indent string) {
+	var widths []int
+	for _, row := range t.rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if width := displayWidth(cell); width > widths[i] {
+				widths[i] = width
+			}
+		}
+	}
+
+	for _, row := range t.rows {
+		var sb strings.Builder
+		sb.WriteString(indent)
+		for i, cell := range row {
+			if i > 0 {
+				sb.WriteString("  ")
+			}
+			sb.WriteString(cell)
+			if i < len(row)-1 {
+				sb.WriteString(strings.Repeat(" ", widths[i]-displayWidth(cell)))
+			}
+		}
+		fmt.Fprintln(w, strings.TrimRight(sb.String(), " "))
+	}
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/OmSingh2003/git-util/pkg/gitops"
+	"github.com/spf13/cobra"
+)
+
+// Variables to hold the flag values for the tag-report command
+var (
+	tagScan   scanFlags
+	tagFormat string
+)
+
+// repoTag is the latest tag of a single repository.
+type repoTag struct {
+	Path         string `json:"path"`
+	RelativePath string `json:"relativePath"`
+	Tag          string `json:"tag,omitempty"` // empty when no tag is reachable from HEAD
+	TagAge       string `json:"tagAge,omitempty"`
+	CommitsSince int    `json:"commitsSince"`
+	Error        string `json:"error,omitempty"`
+}
+
+// tagSummary counts the repositories by release state.
+type tagSummary struct {
+	Repos     int `json:"repos"`
+	Untagged  int `json:"untagged"`
+	Unchanged int `json:"unchanged"` // no commits since the latest tag
+	Changed   int `json:"changed"`   // commits waiting for a release
+	Errors    int `json:"errors"`
+}
+
+// tagReport is the JSON document printed by 'tag-report --format json'.
+type tagReport struct {
+	SchemaVersion int        `json:"schemaVersion"`
+	Repos         []repoTag  `json:"repos"`
+	Summary       tagSummary `json:"summary"`
+}
+
+// tagReportCmd represents the tag-report command
+var tagReportCmd = &cobra.Command{
+	Use:   "tag-report",
+	Short: "Show the latest tag of multiple Git repositories and the commits since.",
+	Long: `Scans a directory for Git repositories and lists, per repository, the most
+recent tag reachable from HEAD ('git describe --tags --abbrev=0'), when the
+tagged commit was made, and how many commits HEAD has on top of it
+('git rev-list <tag>..HEAD --count'). Repositories with many commits since
+their last tag are likely due for a release.
+
+Repositories without any tag are shown as [No tags].
+--format json prints the report as a JSON document.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format := strings.ToLower(tagFormat)
+		if format != "text" && format != "json" {
+			return fmt.Errorf("invalid format '%s': must be 'text' or 'json'", tagFormat)
+		}
+
+		// --- Determine Target Directory ---
+		targetDir, err := resolveTargetDir(tagScan.directory)
+		if err != nil {
+			return err
+		}
+
+		if format == "text" {
+			fmt.Printf("Scanning directory: %s\n", targetDir)
+		}
+
+		// --- Find Repositories ---
+		repos, err := tagScan.findRepos(targetDir)
+		if err != nil {
+			return err
+		}
+
+		// --- Collect the Latest Tag Per Repository ---
+		report := tagReport{SchemaVersion: jsonSchemaVersion, Repos: []repoTag{}}
+		for _, repoPath := range repos {
+			entry := repoTag{Path: repoPath, RelativePath: displayPath(targetDir, repoPath)}
+			tag, since, err := gitops.LatestTag(repoPath)
+			switch {
+			case err != nil:
+				fmt.Fprintf(os.Stderr, "Warning: failed to read tags for %s: %v\n", entry.RelativePath, err)
+				entry.Error = err.Error()
+				report.Summary.Errors++
+			case tag == "":
+				report.Summary.Untagged++
+			default:
+				entry.Tag, entry.CommitsSince = tag, since
+				entry.TagAge, _ = gitops.TagAge(repoPath, tag)
+				if since == 0 {
+					report.Summary.Unchanged++
+				} else {
+					report.Summary.Changed++
+				}
+			}
+			report.Repos = append(report.Repos, entry)
+		}
+		report.Summary.Repos = len(repos)
+
+		// --- Print Report ---
+		if format == "json" {
+			return writeJSON(os.Stdout, report)
+		}
+
+		if len(repos) == 0 {
+			fmt.Println("No Git repositories found in the specified directory.")
+			return nil
+		}
+
+		fmt.Printf("\n--- Latest Tags ---\n")
+		t := &table{}
+		t.addRow("REPOSITORY", "TAG", "TAGGED", "SINCE")
+		for _, entry := range report.Repos {
+			switch {
+			case entry.Error != "":
+				t.addRow(entry.RelativePath, "[Error]")
+			case entry.Tag == "":
+				t.addRow(entry.RelativePath, "[No tags]")
+			default:
+				age := entry.TagAge
+				if age == "" {
+					age = "-"
+				}
+				t.addRow(entry.RelativePath, entry.Tag, age, fmt.Sprintf("%d commits", entry.CommitsSince))
+			}
+		}
+		t.write(os.Stdout, "")
+
+		fmt.Printf("\n--- Summary ---\n")
+		fmt.Printf("  Repositories:           %d\n", report.Summary.Repos)
+		fmt.Printf("  Commits since last tag: %d\n", report.Summary.Changed)
+		fmt.Printf("  Released (no changes):  %d\n", report.Summary.Unchanged)
+		fmt.Printf("  Without tags:           %d\n", report.Summary.Untagged)
+		if report.Summary.Errors > 0 {
+			fmt.Printf("  Errors:                 %d\n", report.Summary.Errors)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tagReportCmd)
+	addScanFlags(tagReportCmd, &tagScan)
+	tagReportCmd.Flags().StringVarP(&tagFormat, "format", "f", "text", "Output format: 'text' or 'json'")
+}
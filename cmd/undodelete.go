@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/OmSingh2003/git-util/pkg/gitops"
+	"github.com/spf13/cobra"
+)
+
+// Variables to hold the flag values for the undo-delete command
+var (
+	undoDeleteScan scanFlags
+	undoDeleteList bool
+)
+
+// undoDeleteCmd represents the undo-delete command
+var undoDeleteCmd = &cobra.Command{
+	Use:   "undo-delete [branch...]",
+	Short: "Recreate branches removed by the branch cleaner.",
+	Long: `Every branch deleted by 'git-util --delete' is recorded, together with the
+commit it pointed to, in .git/git-util/deleted-branches.jsonl. undo-delete
+recreates the named branches from that record, or all recorded branches if no
+names are given. Restored branches are removed from the record; a branch whose
+name is already taken again is skipped and stays recorded.
+
+Without --directory the current repository is used. With --directory every
+repository found below it is processed, matching 'git-util -D'.
+
+--list shows the recorded branches without restoring anything.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if undoDeleteScan.directory == "" {
+			if err := requireRepo(""); err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
+			_, err := undoDelete(args, "")
+			return err
+		}
+
+		// --- Determine Target Directory ---
+		targetDir, err := resolveTargetDir(undoDeleteScan.directory)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Scanning directory: %s\n", targetDir)
+
+		// --- Find Repositories ---
+		repos, err := undoDeleteScan.findRepos(targetDir)
+		if err != nil {
+			return err
+		}
+		if len(repos) == 0 {
+			fmt.Println("No Git repositories found in the specified directory.")
+			return nil
+		}
+
+		// --- Process Each Repository ---
+		restoredTotal := 0
+		for _, repoPath := range repos {
+			fmt.Printf("\n=== %s ===\n", displayPath(targetDir, repoPath))
+			restored, err := undoDelete(args, repoPath)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "  Error: %v\n", err)
+			}
+			restoredTotal += restored
+		}
+
+		if !undoDeleteList {
+			fmt.Printf("\n--- Overall Summary ---\n")
+			fmt.Printf("  Repositories processed: %d\n", len(repos))
+			fmt.Printf("  Branches restored:      %d\n", restoredTotal)
+		}
+		return nil
+	},
+}
+
+// undoDelete lists or restores the recorded deleted branches of the repository
+// at repoPath ("" for the current directory). If names is non-empty only those
+// branches are considered. It returns the number of branches restored.
+func undoDelete(names []string, repoPath string) (int, error) {
+	records, err := gitops.LoadDeletedBranches(repoPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read deleted branch record: %w", err)
+	}
+
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+	selected := func(b gitops.DeletedBranch) bool {
+		return len(wanted) == 0 || wanted[b.Name]
+	}
+
+	if undoDeleteList {
+		if len(records) == 0 {
+			fmt.Println("No deleted branches recorded.")
+		}
+		for _, b := range records {
+			if selected(b) {
+				fmt.Printf("  %s (was %.7s, deleted %s)\n", b.Name, b.SHA, b.DeletedAt.Local().Format("2006-01-02 15:04"))
+			}
+		}
+		return 0, nil
+	}
+
+	// Only the newest record of each selected branch is restored.
+	latest := make(map[string]int)
+	for i, b := range records {
+		if selected(b) {
+			latest[b.Name] = i
+		}
+	}
+	for _, name := range names {
+		if _, ok := latest[name]; !ok {
+			fmt.Printf("No record of a deleted branch named %s.\n", name)
+		}
+	}
+	if len(latest) == 0 {
+		if len(names) == 0 {
+			fmt.Println("No deleted branches recorded.")
+		}
+		return 0, nil
+	}
+
+	restoredNames := make(map[string]bool)
+	for i, b := range records {
+		name := b.Name
+		if !selected(b) || latest[name] != i {
+			continue
+		}
+		if gitops.RefExists(repoPath, "refs/heads/"+name) {
+			fmt.Printf("Skipping %s: a branch with that name already exists.\n", name)
+			continue
+		}
+		if _, err := gitops.RunGitCommand("-C", repoPath, "branch", name, b.SHA); err != nil {
+			fmt.Printf("Failed to restore %s (%v)\n", name, err)
+			continue
+		}
+		fmt.Printf("Restored branch %s at %.7s.\n", name, b.SHA)
+		restoredNames[name] = true
+	}
+
+	// Restored branches leave the record, including older deletions of the same name.
+	var remaining []gitops.DeletedBranch
+	for _, b := range records {
+		if !restoredNames[b.Name] {
+			remaining = append(remaining, b)
+		}
+	}
+	restored := len(restoredNames)
+	if err := gitops.SaveDeletedBranches(repoPath, remaining); err != nil {
+		return restored, fmt.Errorf("failed to update deleted branch record: %w", err)
+	}
+	return restored, nil
+}
+
+func init() {
+	rootCmd.AddCommand(undoDeleteCmd)
+	addScanFlags(undoDeleteCmd, &undoDeleteScan)
+	undoDeleteCmd.Flags().Lookup("directory").Usage = "Restore branches in every repository below this directory (defaults to the current repository)"
+	undoDeleteCmd.Flags().BoolVar(&undoDeleteList, "list", false, "List the recorded deleted branches instead of restoring them")
+}
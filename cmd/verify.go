@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/OmSingh2003/git-util/internal/output"
+	"github.com/OmSingh2003/git-util/pkg/gitops"
+	"github.com/spf13/cobra"
+)
+
+// Variables to hold the flag values for the verify command
+var (
+	verifyScan  scanFlags
+	verifyQuick bool
+	verifyJobs  int
+)
+
+// verifyCmd represents the verify command
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check multiple Git repositories for corruption or missing objects.",
+	Long: `Scans a directory for Git repositories and runs 'git fsck' in each one,
+reporting every repository as OK or CORRUPT. Use it to validate backups or to
+catch disk rot across an archive of repositories.
+
+--quick only checks that HEAD resolves to a commit and tree and that every
+object's header can be read ('git cat-file --batch-check --batch-all-objects').
+It is much faster than a full fsck but does not notice objects missing from
+history or corrupt object contents.
+
+The command exits non-zero if any repository fails verification.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateJobs(verifyJobs); err != nil {
+			return err
+		}
+
+		// --- Determine Target Directory ---
+		targetDir, err := resolveTargetDir(verifyScan.directory)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Scanning directory: %s\n", targetDir)
+
+		// --- Find Repositories ---
+		repos, err := verifyScan.findRepos(targetDir)
+		if err != nil {
+			return err
+		}
+
+		if len(repos) == 0 {
+			fmt.Println("No Git repositories found in the specified directory.")
+			return nil
+		}
+
+		fmt.Printf("\n--- Verifying Repositories ---\n")
+
+		// --- Calculate Max Path Length for Formatting ---
+		names := verifyScan.displayNames(targetDir, repos)
+		maxLen := maxNameLen(names)
+
+		// --- Verify Repositories, Printing Results in Discovery Order ---
+		failed := make([]bool, len(repos))
+		out := output.NewOrdered(os.Stdout, os.Stderr, len(repos))
+		started := runParallel(verifyJobs, len(repos), func(i int) {
+			relPath := names[repos[i]]
+			task := out.Task(i)
+			defer task.Done()
+
+			note, problems, err := verifyRepo(repos[i])
+			if err != nil {
+				task.Printf("%-*s : CORRUPT\n", maxLen, relPath)
+				task.Errorf("  Error for %s: %v\n", relPath, err)
+				if problems != "" {
+					task.Errorf("  Output: %s\n", problems)
+				}
+				failed[i] = true
+				return
+			}
+			task.Printf("%-*s : OK%s\n", maxLen, relPath, note)
+		})
+
+		okCount := 0
+		failCount := 0
+		for _, f := range failed[:started] {
+			if f {
+				failCount++
+			} else {
+				okCount++
+			}
+		}
+
+		// Print summary
+		fmt.Printf("\n--- Summary ---\n")
+		fmt.Printf("  OK:      %d\n", okCount)
+		fmt.Printf("  Corrupt: %d\n", failCount)
+
+		if failCount > 0 {
+			cmd.SilenceUsage = true // the failures are already reported above
+			return fmt.Errorf("%d of %d repositories failed verification", failCount, len(repos))
+		}
+		return nil
+	},
+}
+
+// verifyRepo checks the object database of the repository at repoPath, with a
+// full 'git fsck' or, with --quick, only the cheap checks. On failure problems
+// holds what git reported on standard output, e.g. the missing objects.
+func verifyRepo(repoPath string) (note, problems string, err error) {
+	if !verifyQuick {
+		problems, err = gitops.RunGitCommand("-C", repoPath, "fsck", "--full", "--no-progress", "--no-dangling")
+		return "", problems, err
+	}
+
+	if !gitops.RefExists(repoPath, "HEAD") {
+		// An unborn HEAD is a fresh repository, not a broken one, if HEAD itself is a symbolic ref.
+		if _, err := gitops.RunGitCommand("-C", repoPath, "symbolic-ref", "--quiet", "HEAD"); err != nil {
+			return "", "", fmt.Errorf("HEAD is missing or invalid")
+		}
+		note = " (no commits)"
+	} else if _, err := gitops.RunGitCommand("-C", repoPath, "cat-file", "-e", "HEAD^{tree}"); err != nil {
+		return "", "", fmt.Errorf("HEAD does not point to a readable commit and tree")
+	}
+	// Reading every object's header fails on corrupt packs and loose objects.
+	if _, err := gitops.RunGitCommand("-C", repoPath, "cat-file", "--batch-check=%(objecttype)", "--batch-all-objects"); err != nil {
+		return "", "", err
+	}
+	return note, "", nil
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+	addScanFlags(verifyCmd, &verifyScan)
+	verifyCmd.Flags().BoolVar(&verifyQuick, "quick", false, "Only check that HEAD resolves and that all objects are readable, instead of a full 'git fsck'")
+	verifyCmd.Flags().IntVarP(&verifyJobs, "jobs", "j", 1, "Number of repositories to verify in parallel")
+}
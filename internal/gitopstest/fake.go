@@ -0,0 +1,68 @@
+// Package gitopstest provides a fake git runner, so that code built on
+// pkg/gitops can be tested without spawning git. Install it with
+// 'defer gitops.SetRunner(fake)()'.
+package gitopstest
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Response is the canned result of one git invocation on a FakeRunner.
+type Response struct {
+	Output string
+	Err    error
+}
+
+// FakeRunner is a gitops.Runner that never spawns git. It answers each
+// invocation from a table keyed by the space-joined arguments and records every
+// call. It is safe for concurrent use.
+type FakeRunner struct {
+	mu        sync.Mutex
+	responses map[string]Response
+	calls     [][]string
+}
+
+// NewFakeRunner returns an empty FakeRunner.
+func NewFakeRunner() *FakeRunner {
+	return &FakeRunner{responses: make(map[string]Response)}
+}
+
+// On registers the output and error returned when git is invoked with exactly args.
+func (f *FakeRunner) On(output string, err error, args ...string) *FakeRunner {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses[strings.Join(args, " ")] = Response{Output: output, Err: err}
+	return f
+}
+
+// Run returns the registered response for args, or an error for unexpected invocations.
+func (f *FakeRunner) Run(args ...string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, append([]string(nil), args...))
+	resp, ok := f.responses[strings.Join(args, " ")]
+	if !ok {
+		return "", fmt.Errorf("fake runner: unexpected command 'git %s'", strings.Join(args, " "))
+	}
+	return resp.Output, resp.Err
+}
+
+// Calls returns a copy of the argument lists of every invocation so far, in order.
+func (f *FakeRunner) Calls() [][]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([][]string(nil), f.calls...)
+}
+
+// Called reports whether git was invoked with exactly args.
+func (f *FakeRunner) Called(args ...string) bool {
+	want := strings.Join(args, " ")
+	for _, call := range f.Calls() {
+		if strings.Join(call, " ") == want {
+			return true
+		}
+	}
+	return false
+}
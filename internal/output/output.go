@@ -0,0 +1,128 @@
+// Package output keeps the output of repositories processed in parallel
+// readable: each repository's output is buffered and written in one piece, in
+// a fixed order, instead of interleaving with the others line by line.
+package output
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Ordered collects the output of a fixed number of tasks, numbered 0 to n-1.
+// A task's output is written to the underlying writers as soon as the task and
+// every task before it are done, so the combined output always appears in task
+// order no matter in which order the tasks finish. It is safe for concurrent use.
+type Ordered struct {
+	mu     sync.Mutex
+	stdout io.Writer
+	stderr io.Writer
+	tasks  []*Task
+	next   int  // first task not yet flushed
+	held   bool // set by Hold: keep all output until Release
+}
+
+// NewOrdered returns an Ordered for n tasks writing to stdout and stderr.
+func NewOrdered(stdout, stderr io.Writer, n int) *Ordered {
+	o := &Ordered{stdout: stdout, stderr: stderr, tasks: make([]*Task, n)}
+	for i := range o.tasks {
+		o.tasks[i] = &Task{o: o}
+	}
+	return o
+}
+
+// Task returns the output buffer of task i.
+func (o *Ordered) Task(i int) *Task {
+	return o.tasks[i]
+}
+
+// Hold keeps the output of tasks that finish from now on buffered until
+// Release is called, e.g. to print all results only once the work is done.
+func (o *Ordered) Hold() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.held = true
+}
+
+// Release ends Hold and writes out the output of the tasks that are done.
+func (o *Ordered) Release() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.held = false
+	o.flush()
+}
+
+// flush writes out every finished task that has no unfinished task before it.
+// The caller must hold o.mu.
+func (o *Ordered) flush() {
+	if o.held {
+		return
+	}
+	for o.next < len(o.tasks) && o.tasks[o.next].done {
+		for _, c := range o.tasks[o.next].chunks {
+			if c.stderr {
+				o.stderr.Write(c.data)
+			} else {
+				o.stdout.Write(c.data)
+			}
+		}
+		o.tasks[o.next].chunks = nil
+		o.next++
+	}
+}
+
+// chunk is one write to a task's stdout or stderr.
+type chunk struct {
+	stderr bool
+	data   []byte
+}
+
+// Task buffers the output of one task until Done is called. Its writers must
+// only be used by the goroutine running the task.
+type Task struct {
+	o      *Ordered
+	chunks []chunk
+	done   bool
+}
+
+// Stdout returns a writer whose output goes to the standard output stream.
+func (t *Task) Stdout() io.Writer { return taskWriter{t, false} }
+
+// Stderr returns a writer whose output goes to the error stream. Writes to
+// Stdout and Stderr keep their relative order.
+func (t *Task) Stderr() io.Writer { return taskWriter{t, true} }
+
+// Printf formats to the task's standard output.
+func (t *Task) Printf(format string, args ...any) {
+	fmt.Fprintf(t.Stdout(), format, args...)
+}
+
+// Errorf formats to the task's error stream.
+func (t *Task) Errorf(format string, args ...any) {
+	fmt.Fprintf(t.Stderr(), format, args...)
+}
+
+// Done marks the task as finished, writing its output (and that of any
+// finished tasks waiting on it) if every earlier task is done too. Nothing
+// may be written to the task afterwards.
+func (t *Task) Done() {
+	t.o.mu.Lock()
+	defer t.o.mu.Unlock()
+	t.done = true
+	t.o.flush()
+}
+
+// taskWriter appends writes to one stream of a task.
+type taskWriter struct {
+	t      *Task
+	stderr bool
+}
+
+func (w taskWriter) Write(p []byte) (int, error) {
+	if n := len(w.t.chunks); n > 0 && w.t.chunks[n-1].stderr == w.stderr {
+		w.t.chunks[n-1].data = append(w.t.chunks[n-1].data, p...)
+	} else {
+		w.t.chunks = append(w.t.chunks, chunk{stderr: w.stderr, data: append([]byte(nil), p...)})
+	}
+	return len(p), nil
+}
@@ -0,0 +1,81 @@
+// Package config loads git-util's YAML configuration: a global file in the
+// user's config directory and optional per-repository overrides.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RepoFileName is the name of the per-repository override file, looked up at
+// the root of each repository's working tree.
+const RepoFileName = ".git-util.yaml"
+
+// Config holds the settings that can be given in a configuration file instead
+// of on the command line. Command-line flags always take precedence.
+type Config struct {
+	// Main is the repository's main branch, used instead of auto-detection.
+	Main string `yaml:"main,omitempty"`
+	// Exclude lists branch names or glob patterns the branch cleaner never deletes.
+	Exclude []string `yaml:"exclude,omitempty"`
+}
+
+// GlobalPath returns the location of the global configuration file,
+// e.g. ~/.config/git-util/config.yaml on Linux.
+func GlobalPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "git-util", "config.yaml"), nil
+}
+
+// Load reads the configuration file at path. A missing file yields an empty Config.
+func Load(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+		return cfg, err
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("invalid configuration in %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// LoadGlobal reads the global configuration file, if there is one.
+func LoadGlobal() (Config, error) {
+	path, err := GlobalPath()
+	if err != nil {
+		return Config{}, nil // no config directory, nothing to load
+	}
+	return Load(path)
+}
+
+// ForRepo returns c with the overrides from the repository's .git-util.yaml
+// merged over it. An empty repoPath means the current directory.
+func (c Config) ForRepo(repoPath string) (Config, error) {
+	repoCfg, err := Load(filepath.Join(repoPath, RepoFileName))
+	if err != nil {
+		return c, err
+	}
+	return c.Merge(repoCfg), nil
+}
+
+// Merge returns c overlaid with over: scalar settings set in over replace those
+// in c, and list settings are combined.
+func (c Config) Merge(over Config) Config {
+	merged := c
+	if over.Main != "" {
+		merged.Main = over.Main
+	}
+	merged.Exclude = append(append([]string(nil), c.Exclude...), over.Exclude...)
+	return merged
+}
@@ -0,0 +1,197 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Keys lists the settings 'git-util config' can read and write, in the order
+// they are documented.
+var Keys = []string{"main", "exclude"}
+
+// ValidateKey returns an error for keys that are not settings, suggesting the
+// closest valid one.
+func ValidateKey(key string) error {
+	for _, k := range Keys {
+		if k == key {
+			return nil
+		}
+	}
+	best, bestDist := "", -1
+	for _, k := range Keys {
+		if d := editDistance(key, k); bestDist < 0 || d < bestDist {
+			best, bestDist = k, d
+		}
+	}
+	if bestDist <= max(2, len(best)/2) {
+		return fmt.Errorf("unknown configuration key %q (did you mean %q?)", key, best)
+	}
+	return fmt.Errorf("unknown configuration key %q (valid keys: %s)", key, strings.Join(Keys, ", "))
+}
+
+// Get returns the value of key in c as it is printed by 'git-util config get':
+// the branch name for main, one pattern per line for exclude. ok is false if
+// the key is not set.
+func (c Config) Get(key string) (value string, ok bool, err error) {
+	if err := ValidateKey(key); err != nil {
+		return "", false, err
+	}
+	switch key {
+	case "main":
+		return c.Main, c.Main != "", nil
+	case "exclude":
+		return strings.Join(c.Exclude, "\n"), len(c.Exclude) > 0, nil
+	}
+	return "", false, nil
+}
+
+// parseValue checks value for key and returns the YAML node to store. main
+// takes a branch name; exclude takes a comma-separated list of branch names or
+// glob patterns. An empty value removes the key (nil node).
+func parseValue(key, value string) (*yaml.Node, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	switch key {
+	case "main":
+		if strings.ContainsAny(value, " \t\n") {
+			return nil, fmt.Errorf("invalid value for main: %q is not a branch name", value)
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}, nil
+	case "exclude":
+		seq := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, pattern := range strings.Split(value, ",") {
+			pattern = strings.TrimSpace(pattern)
+			if pattern == "" {
+				continue
+			}
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid pattern %q for exclude: %w", pattern, err)
+			}
+			seq.Content = append(seq.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: pattern})
+		}
+		return seq, nil
+	}
+	return nil, ValidateKey(key)
+}
+
+// Set writes key = value into the configuration file at path, creating it if
+// needed. Other keys and comments in the file are kept. An empty value removes
+// the key. The result is checked to load as a Config before it is written.
+func Set(path, key, value string) error {
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+	valueNode, err := parseValue(key, value)
+	if err != nil {
+		return err
+	}
+
+	var doc yaml.Node
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if text := strings.TrimSpace(string(data)); text != "" {
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("invalid configuration in %s: %w", path, err)
+		}
+		if doc.Kind == 0 {
+			// yaml.v3 returns nothing for a file holding only comments; keep them as its header.
+			doc.HeadComment = text
+		}
+	}
+	if len(doc.Content) == 0 || isEmptyNode(doc.Content[0]) {
+		// An empty document ("---", "~") gets a fresh mapping with its comments.
+		mapping := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		if len(doc.Content) > 0 {
+			old := doc.Content[0]
+			mapping.HeadComment, mapping.LineComment, mapping.FootComment = old.HeadComment, old.LineComment, old.FootComment
+		}
+		// yaml.v3 attaches a comment above "---" to the end of an empty document.
+		doc.HeadComment = strings.TrimSpace(doc.HeadComment + "\n" + doc.FootComment)
+		doc.FootComment = ""
+		doc.Kind, doc.Content = yaml.DocumentNode, []*yaml.Node{mapping}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("invalid configuration in %s: expected a mapping of settings", path)
+	}
+	setMappingValue(root, key, valueNode)
+
+	var out strings.Builder
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	enc.Close()
+	var check Config
+	if err := yaml.Unmarshal([]byte(out.String()), &check); err != nil {
+		return fmt.Errorf("refusing to write invalid configuration to %s: %w", path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(out.String()), 0o644)
+}
+
+// isEmptyNode reports whether n is a null or empty scalar, the root of a YAML
+// document without settings.
+func isEmptyNode(n *yaml.Node) bool {
+	return n.Kind == yaml.ScalarNode && (n.Tag == "!!null" || n.Value == "")
+}
+
+// setMappingValue replaces the value of key in mapping, appends it if the key
+// is missing, or removes the pair if value is nil.
+func setMappingValue(mapping *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value != key {
+			continue
+		}
+		if value == nil {
+			// A comment above the removed key (often the file's header) moves to the next one.
+			if comment := mapping.Content[i].HeadComment; comment != "" && i+2 < len(mapping.Content) {
+				next := mapping.Content[i+2]
+				next.HeadComment = strings.TrimSpace(comment + "\n" + next.HeadComment)
+			}
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+		} else {
+			// Keep a comment written after the old value, e.g. "main: dev  # why".
+			value.LineComment = mapping.Content[i+1].LineComment
+			mapping.Content[i+1] = value
+		}
+		return
+	}
+	if value != nil {
+		mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+	}
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string // "" for a missing file
+		key      string
+		value    string
+		want     string
+	}{
+		{name: "new file", key: "main", value: "dev", want: "main: dev\n"},
+		{name: "empty file", existing: "\n", key: "main", value: "dev", want: "main: dev\n"},
+		{
+			name:     "only comments",
+			existing: "# just a header comment\n",
+			key:      "main", value: "dev",
+			want: "# just a header comment\n\nmain: dev\n",
+		},
+		{name: "only a document marker", existing: "---\n", key: "main", value: "dev", want: "main: dev\n"},
+		{
+			name:     "comment before a document marker",
+			existing: "# header\n---\n",
+			key:      "main", value: "dev",
+			want: "# header\n\nmain: dev\n",
+		},
+		{
+			name:     "commented null document",
+			existing: "# header\nnull\n",
+			key:      "main", value: "dev",
+			want: "# header\nmain: dev\n",
+		},
+		{
+			name:     "replace keeps comments",
+			existing: "# header\nmain: master # why\nexclude: [old-*]\n",
+			key:      "main", value: "dev",
+			want: "# header\nmain: dev # why\nexclude: [old-*]\n",
+		},
+		{
+			name:     "remove moves the header to the next key",
+			existing: "# header\nmain: master\nexclude: [old-*]\n",
+			key:      "main", value: "",
+			want: "# header\nexclude: [old-*]\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".git-util.yaml")
+			if tt.existing != "" {
+				if err := os.WriteFile(path, []byte(tt.existing), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := Set(path, tt.key, tt.value); err != nil {
+				t.Fatalf("Set() error = %v", err)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Set() wrote\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetRejectsNonMapping(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".git-util.yaml")
+	if err := os.WriteFile(path, []byte("- main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Set(path, "main", "dev"); err == nil {
+		t.Error("Set() on a list succeeded, want an error")
+	}
+}
@@ -0,0 +1,104 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// httpClient is shared by all forge API calls.
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// nextLinkPattern extracts the rel="next" URL from a GitHub Link header.
+var nextLinkPattern = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// GitHubAPIBase returns the REST API root for a GitHub host. github.com uses
+// api.github.com; any other host is assumed to be GitHub Enterprise Server.
+func GitHubAPIBase(host string) string {
+	if host == "github.com" {
+		return "https://api.github.com"
+	}
+	return "https://" + host + "/api/v3"
+}
+
+// getGitHubJSON performs an authenticated GET against the GitHub API and decodes
+// the JSON body into v. It returns the URL of the next page, if any.
+func getGitHubJSON(ctx context.Context, apiURL, token string, v any) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("GitHub API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GitHub API request to %s failed: %s", apiURL, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return "", fmt.Errorf("failed to decode GitHub API response: %w", err)
+	}
+
+	next := ""
+	if m := nextLinkPattern.FindStringSubmatch(resp.Header.Get("Link")); m != nil {
+		next = m[1]
+	}
+	return next, nil
+}
+
+// GitHubProtectedBranches lists the names of the protected branches of r.
+// Reading branch protection generally requires a token with access to the repository.
+func GitHubProtectedBranches(ctx context.Context, r Remote, token string) ([]string, error) {
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/branches?protected=true&per_page=100", GitHubAPIBase(r.Host), r.Owner, r.Repo)
+
+	var names []string
+	for apiURL != "" {
+		var page []struct {
+			Name string `json:"name"`
+		}
+		next, err := getGitHubJSON(ctx, apiURL, token, &page)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range page {
+			names = append(names, b.Name)
+		}
+		apiURL = next
+	}
+	return names, nil
+}
+
+// IsGitHubHost reports whether host is github.com or looks like a GitHub
+// Enterprise Server, i.e. has "github" in its name.
+func IsGitHubHost(host string) bool {
+	return host == "github.com" || strings.Contains(strings.ToLower(host), "github")
+}
+
+// GitHubOpenPullRequest returns the number of the open pull request of r whose
+// head is branch in r itself, or 0 if there is none. Public repositories can
+// be queried without a token, at a low rate limit.
+func GitHubOpenPullRequest(ctx context.Context, r Remote, branch, token string) (int, error) {
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/pulls?state=open&per_page=1&head=%s", GitHubAPIBase(r.Host), r.Owner, r.Repo, url.QueryEscape(r.Owner+":"+branch))
+	var pulls []struct {
+		Number int `json:"number"`
+	}
+	if _, err := getGitHubJSON(ctx, apiURL, token, &pulls); err != nil {
+		return 0, err
+	}
+	if len(pulls) == 0 {
+		return 0, nil
+	}
+	return pulls[0].Number, nil
+}
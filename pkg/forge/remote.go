@@ -0,0 +1,61 @@
+// Package forge talks to hosted Git services (GitHub and friends) about the
+// repositories git-util manages.
+package forge
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Remote identifies a repository on a hosting service, as parsed from a remote URL.
+type Remote struct {
+	Host  string // e.g. "github.com"
+	Owner string // user or organization; may contain '/' for nested groups
+	Repo  string // repository name without the ".git" suffix
+}
+
+// ParseRemoteURL extracts host, owner and repository name from a Git remote URL.
+// It understands https://, ssh://, git:// and scp-like (git@host:owner/repo.git) forms.
+func ParseRemoteURL(rawURL string) (Remote, error) {
+	var host, path string
+	if strings.Contains(rawURL, "://") {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return Remote{}, fmt.Errorf("invalid remote URL %q: %w", rawURL, err)
+		}
+		host, path = u.Hostname(), u.Path
+	} else if at := strings.Index(rawURL, ":"); at > 0 {
+		// scp-like syntax: [user@]host:owner/repo.git
+		host, path = rawURL[:at], rawURL[at+1:]
+		if i := strings.LastIndex(host, "@"); i >= 0 {
+			host = host[i+1:]
+		}
+	} else {
+		return Remote{}, fmt.Errorf("remote URL %q does not point to a hosted repository", rawURL)
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	i := strings.LastIndex(path, "/")
+	if host == "" || i <= 0 || i == len(path)-1 {
+		return Remote{}, fmt.Errorf("could not determine owner and repository from remote URL %q", rawURL)
+	}
+	return Remote{Host: strings.ToLower(host), Owner: path[:i], Repo: path[i+1:]}, nil
+}
+
+// PullRequestRef returns the ref under which the hosting service at host
+// publishes the head commit of pull request number. GitLab calls them merge
+// requests (refs/merge-requests/<n>/head); GitHub and services following its
+// layout, such as Gitea, use refs/pull/<n>/head.
+func PullRequestRef(host string, number int) string {
+	if IsGitLabHost(host) {
+		return fmt.Sprintf("refs/merge-requests/%d/head", number)
+	}
+	return fmt.Sprintf("refs/pull/%d/head", number)
+}
+
+// IsGitLabHost reports whether host looks like a GitLab instance: gitlab.com or
+// a self-hosted server with "gitlab" in its name.
+func IsGitLabHost(host string) bool {
+	return strings.Contains(strings.ToLower(host), "gitlab")
+}
@@ -0,0 +1,86 @@
+package gitops
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BackupChanges saves the local changes of the repository at repoPath into the
+// directory dest before they are discarded. With tracked, the uncommitted
+// changes to tracked files are written to dest/changes.patch (restore with
+// 'git apply'); with untracked, the untracked, non-ignored files are copied to
+// dest/untracked, keeping their paths. It returns the paths it wrote.
+func BackupChanges(repoPath, dest string, tracked, untracked bool) ([]string, error) {
+	if err := os.MkdirAll(dest, 0o755); err != nil {
+		return nil, err
+	}
+	var written []string
+	if tracked {
+		patchPath := filepath.Join(dest, "changes.patch")
+		// --output lets git write the file itself, so the patch is not trimmed like RunGitCommand output.
+		if _, err := RunGitCommand("-C", repoPath, "diff", "--binary", "--output="+patchPath, "HEAD"); err != nil {
+			return written, err
+		}
+		if info, err := os.Stat(patchPath); err == nil && info.Size() == 0 {
+			os.Remove(patchPath) // only untracked changes
+		} else {
+			written = append(written, patchPath)
+		}
+	}
+	if untracked {
+		output, err := RunGitCommand("-C", repoPath, "ls-files", "--others", "--exclude-standard", "-z")
+		if err != nil {
+			return written, err
+		}
+		untrackedDir := filepath.Join(dest, "untracked")
+		copied := false
+		for _, name := range strings.Split(output, "\x00") {
+			if name == "" {
+				continue
+			}
+			if err := copyFile(filepath.Join(repoPath, name), filepath.Join(untrackedDir, name)); err != nil {
+				return written, fmt.Errorf("failed to back up %s: %w", name, err)
+			}
+			copied = true
+		}
+		if copied {
+			written = append(written, untrackedDir)
+		}
+	}
+	return written, nil
+}
+
+// copyFile copies the regular file or symlink src to dst, creating parent directories.
+func copyFile(src, dst string) error {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+		return os.Symlink(target, dst)
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,56 @@
+package gitops
+
+import (
+	"strings"
+)
+
+// BlameAuthor identifies the author of blamed lines.
+type BlameAuthor struct {
+	Name  string
+	Email string
+}
+
+// TrackedFiles returns the files in the index of the repository at repoPath
+// that match pathspecs (git pathspecs such as "src/*.go"; none means every
+// file), relative to the repository root. Submodules are left out.
+func TrackedFiles(repoPath string, pathspecs ...string) ([]string, error) {
+	args := append([]string{"-C", repoPath, "ls-files", "-z", "--stage", "--full-name", "--"}, pathspecs...)
+	output, err := RunGitCommand(args...)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	seen := make(map[string]bool) // a conflicted file is listed once per stage
+	for _, entry := range strings.Split(output, "\x00") {
+		meta, file, ok := strings.Cut(entry, "\t")
+		if !ok || strings.HasPrefix(meta, "160000 ") || seen[file] {
+			continue // empty trailing entry, or a submodule (gitlink)
+		}
+		seen[file] = true
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// BlameLineAuthors runs 'git blame --line-porcelain' on file as of HEAD and
+// returns how many of its lines each author (by name and email) wrote. root
+// is the top level of the repository and file is relative to it.
+func BlameLineAuthors(root, file string) (map[BlameAuthor]int, error) {
+	output, err := RunGitCommand("-C", root, "blame", "--line-porcelain", "HEAD", "--", file)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[BlameAuthor]int)
+	var name string
+	for _, line := range strings.Split(output, "\n") {
+		switch {
+		case strings.HasPrefix(line, "author "):
+			name = strings.TrimPrefix(line, "author ")
+		case strings.HasPrefix(line, "author-mail "):
+			// Each line's header has the name right before the email.
+			email := strings.Trim(strings.TrimPrefix(line, "author-mail "), "<>")
+			counts[BlameAuthor{Name: name, Email: email}]++
+		}
+	}
+	return counts, nil
+}
@@ -0,0 +1,105 @@
+package gitops
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// lookupCache memoizes per-repository facts that several steps of one
+// invocation ask for (default branch, upstream presence), so each is computed
+// by spawning git at most once per repository. It is safe for concurrent use.
+type lookupCache struct {
+	mu            sync.Mutex
+	defaultBranch map[string]cachedBranch
+	hasUpstream   map[string]bool // keyed by upstreamKey
+}
+
+// cachedBranch is a memoized DetectDefaultMainBranchIn result, including failures.
+type cachedBranch struct {
+	name string
+	err  error
+}
+
+var cache = newLookupCache()
+
+func newLookupCache() *lookupCache {
+	return &lookupCache{
+		defaultBranch: make(map[string]cachedBranch),
+		hasUpstream:   make(map[string]bool),
+	}
+}
+
+// ResetCache forgets everything memoized so far. Long-running modes that rescan
+// repeatedly (e.g. 'status --watch') call it before every cycle.
+func ResetCache() {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.defaultBranch = make(map[string]cachedBranch)
+	cache.hasUpstream = make(map[string]bool)
+}
+
+// cacheKey normalizes repoPath so "", "." and the absolute path share an entry.
+func cacheKey(repoPath string) string {
+	if abs, err := filepath.Abs(repoPath); err == nil {
+		return abs
+	}
+	return repoPath
+}
+
+// upstreamKey is the hasUpstream key of repoPath. Whether there is an upstream
+// depends on the branch checked out, so the content of HEAD (read from the git
+// directory, without spawning git) is part of the key: after a checkout the
+// answer for the previous branch is not reused.
+func upstreamKey(repoPath string) string {
+	head := ""
+	if gitDir, err := GitDir(repoPath); err == nil {
+		if data, err := os.ReadFile(filepath.Join(gitDir, "HEAD")); err == nil {
+			head = strings.TrimSpace(string(data))
+		}
+	}
+	return cacheKey(repoPath) + "\x00" + head
+}
+
+func (c *lookupCache) getDefaultBranch(repoPath string) (cachedBranch, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.defaultBranch[cacheKey(repoPath)]
+	return v, ok
+}
+
+func (c *lookupCache) setDefaultBranch(repoPath string, v cachedBranch) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.defaultBranch[cacheKey(repoPath)] = v
+}
+
+func (c *lookupCache) getHasUpstream(repoPath string) (bool, bool) {
+	key := upstreamKey(repoPath) // read HEAD outside the lock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.hasUpstream[key]
+	return v, ok
+}
+
+func (c *lookupCache) setHasUpstream(repoPath string, v bool) {
+	key := upstreamKey(repoPath)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hasUpstream[key] = v
+}
+
+// HasUpstream reports whether the current branch of the repository at repoPath
+// has an upstream branch configured. The answer is cached per branch for the
+// rest of the run.
+func HasUpstream(repoPath string) (bool, error) {
+	if v, ok := cache.getHasUpstream(repoPath); ok {
+		return v, nil
+	}
+	_, _, err := AheadBehind(repoPath, "@{u}") // records the answer in the cache
+	if err != nil && err != ErrNoUpstream {
+		return false, err
+	}
+	return err == nil, nil
+}
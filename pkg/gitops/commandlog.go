@@ -0,0 +1,91 @@
+package gitops
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// CommandLogEntry is one line of the command log: a single git invocation.
+type CommandLogEntry struct {
+	Time       time.Time `json:"time"`
+	Repo       string    `json:"repo,omitempty"` // the -C argument, if any
+	Args       []string  `json:"args"`
+	ExitCode   int       `json:"exitCode"` // -1 if git could not be started
+	DurationMs float64   `json:"durationMs"`
+}
+
+// LoggingRunner is a Runner that passes every invocation on to another Runner
+// and appends a CommandLogEntry for it, as one JSON object per line, to a writer.
+// It is safe for concurrent use.
+type LoggingRunner struct {
+	next Runner
+	mu   sync.Mutex
+	w    io.Writer
+	err  error // first write error; later entries are dropped
+}
+
+// NewLoggingRunner returns a LoggingRunner that runs commands with next and logs them to w.
+func NewLoggingRunner(next Runner, w io.Writer) *LoggingRunner {
+	return &LoggingRunner{next: next, w: w}
+}
+
+// Run executes args with the wrapped Runner and logs the invocation.
+func (r *LoggingRunner) Run(args ...string) (string, error) {
+	return r.RunContext(context.Background(), args...)
+}
+
+// RunContext is Run, passing ctx on to the wrapped Runner.
+func (r *LoggingRunner) RunContext(ctx context.Context, args ...string) (string, error) {
+	start := time.Now()
+	output, err := runContext(ctx, r.next, args...)
+	entry := CommandLogEntry{
+		Time:       start,
+		Args:       redactArgs(args), // e.g. a clone URL with a token
+		ExitCode:   exitCode(err),
+		DurationMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if len(args) >= 2 && args[0] == "-C" {
+		entry.Repo = args[1]
+	}
+	r.log(entry)
+	return output, err
+}
+
+// Err returns the first error encountered while writing the log, if any.
+func (r *LoggingRunner) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+func (r *LoggingRunner) log(entry CommandLogEntry) {
+	data, err := json.Marshal(entry)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return
+	}
+	if err == nil {
+		_, err = fmt.Fprintln(r.w, string(data))
+	}
+	r.err = err
+}
+
+// exitCode extracts the process exit status from a Runner error: 0 on success,
+// -1 if git did not run at all.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
@@ -0,0 +1,126 @@
+package gitops
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// DeletedBranch records a branch removed by the cleaner, so that it can be
+// recreated later with 'git-util undo-delete'.
+type DeletedBranch struct {
+	Name      string    `json:"name"`
+	SHA       string    `json:"sha"`
+	DeletedAt time.Time `json:"deletedAt"`
+}
+
+// deletedBranchPattern matches the line 'git branch -d' prints for each branch,
+// e.g. "Deleted branch feature-x (was 1a2b3c4).".
+var deletedBranchPattern = regexp.MustCompile(`Deleted branch (\S+) \(was ([0-9a-f]+)\)`)
+
+// ParseDeletedBranch extracts the branch name and abbreviated SHA from the
+// output of 'git branch -d'.
+func ParseDeletedBranch(output string) (name, sha string, ok bool) {
+	m := deletedBranchPattern.FindStringSubmatch(output)
+	if m == nil {
+		return "", "", false
+	}
+	return m[1], m[2], true
+}
+
+// deletedBranchesFile returns the path of the record file inside the git
+// directory of the repository at repoPath, where it is safe from 'git clean'.
+// Branches are shared by all worktrees, so it is the common git directory,
+// found from any subdirectory as well.
+func deletedBranchesFile(repoPath string) (string, error) {
+	if repoPath == "" {
+		repoPath = "."
+	}
+	gitDir, err := CommonGitDir(repoPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(gitDir, "git-util", "deleted-branches.jsonl"), nil
+}
+
+// RecordDeletedBranch appends b to the deleted-branch record of the repository at repoPath.
+func RecordDeletedBranch(repoPath string, b DeletedBranch) error {
+	path, err := deletedBranchesFile(repoPath)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = fmt.Fprintln(f, string(data))
+	return err
+}
+
+// LoadDeletedBranches returns the recorded deleted branches of the repository at
+// repoPath, oldest first. A repository without a record yields none.
+func LoadDeletedBranches(repoPath string) ([]DeletedBranch, error) {
+	path, err := deletedBranchesFile(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var branches []DeletedBranch
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var b DeletedBranch
+		if err := json.Unmarshal(scanner.Bytes(), &b); err != nil {
+			return nil, fmt.Errorf("corrupt record in %s: %w", path, err)
+		}
+		branches = append(branches, b)
+	}
+	return branches, scanner.Err()
+}
+
+// SaveDeletedBranches replaces the deleted-branch record of the repository at
+// repoPath with branches, removing the record file when none are left.
+func SaveDeletedBranches(repoPath string, branches []DeletedBranch) error {
+	path, err := deletedBranchesFile(repoPath)
+	if err != nil {
+		return err
+	}
+	if len(branches) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	var data []byte
+	for _, b := range branches {
+		line, err := json.Marshal(b)
+		if err != nil {
+			return err
+		}
+		data = append(append(data, line...), '\n')
+	}
+	return os.WriteFile(path, data, 0o644)
+}
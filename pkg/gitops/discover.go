@@ -0,0 +1,219 @@
+package gitops
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FindOptions controls how FindGitReposWithOptions discovers repositories.
+type FindOptions struct {
+	// ExcludeSubmodules drops repositories that are registered as a submodule
+	// (in .gitmodules) of another discovered repository.
+	ExcludeSubmodules bool
+	// ExcludeDirs lists absolute directory paths or glob patterns (matched
+	// against the absolute path) whose subtrees are not scanned. A plain path
+	// also excludes everything below it.
+	ExcludeDirs []string
+	// MaxRepos caps the number of repositories returned, as a guard against
+	// scanning far more than intended (e.g. '/'): the walk stops when one more
+	// is found. 0 means no limit.
+	MaxRepos int
+	// Parallel reads up to this many directories at a time instead of walking
+	// the tree serially, which is much faster on network filesystems. The
+	// result is sorted into serial walk order. 0 or 1 walks serially.
+	Parallel int
+}
+
+// FindResult is the outcome of a repository scan.
+type FindResult struct {
+	Repos  []string    // absolute paths of repository roots, in walk order
+	Errors []WalkError // paths that could not be read; their subtrees were not scanned
+	// ExcludedDirs counts the subtrees skipped because they matched FindOptions.ExcludeDirs.
+	ExcludedDirs int
+	// LimitReached reports that there are more than FindOptions.MaxRepos
+	// repositories and the walk stopped early.
+	LimitReached bool
+}
+
+// WalkError is a path the scan could not access.
+type WalkError struct {
+	Path string
+	Err  error
+}
+
+// FindGitRepos walks the directory tree starting from rootDir and finds paths
+// containing a .git subdirectory, indicating a Git repository root.
+// Directories matching a pattern in rootDir/.gitutilignore are skipped.
+func FindGitRepos(rootDir string) ([]string, error) { //takes input rootDir and returns path to .git
+	result, err := FindGitReposWithOptions(rootDir, FindOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return result.Repos, nil
+}
+
+// FindGitReposWithOptions is FindGitRepos with additional filtering controlled by opts.
+// A '.git' file (as used by submodules and linked worktrees) also marks a repository root,
+// and bare repositories (see IsBareRepo) are reported by their own path.
+func FindGitReposWithOptions(rootDir string, opts FindOptions) (*FindResult, error) {
+	ignore, err := LoadIgnoreFile(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Parallel > 1 {
+		result := walkParallel(rootDir, ignore, opts)
+		if opts.ExcludeSubmodules {
+			result.Repos = excludeSubmodules(result.Repos)
+		}
+		return result, nil
+	}
+	var repos []string // take as empty string slice
+	var walkErrors []WalkError
+	excludedDirs := 0
+	limitReached := false
+	// addRepo records a repository, or reports false if MaxRepos are already known.
+	addRepo := func(repoPath string) bool {
+		if opts.MaxRepos > 0 && len(repos) >= opts.MaxRepos {
+			limitReached = true
+			return false
+		}
+		repos = append(repos, repoPath)
+		return true
+	}
+	err = filepath.WalkDir(rootDir, func(path string, d os.DirEntry, err error) error { // using filepath.WalkDir, walks and visits every file/directory
+		if err != nil { // recorded for the caller, who decides whether an incomplete scan is acceptable
+			walkErrors = append(walkErrors, WalkError{Path: path, Err: err})
+			return filepath.SkipDir
+		}
+		if d.IsDir() && path != rootDir {
+			if relPath, relErr := filepath.Rel(rootDir, path); relErr == nil && ignore.Match(relPath) {
+				return filepath.SkipDir
+			}
+			if matchesExcludeDir(path, opts.ExcludeDirs) {
+				excludedDirs++
+				return filepath.SkipDir
+			}
+		}
+		if d.Name() == ".git" {
+			if !addRepo(filepath.Dir(path)) {
+				return filepath.SkipAll
+			}
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() && IsBareRepo(path) {
+			if !addRepo(path) {
+				return filepath.SkipAll
+			}
+			return filepath.SkipDir
+		}
+		if d.IsDir() && isSkippedBuildDir(d.Name()) {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.ExcludeSubmodules {
+		repos = excludeSubmodules(repos)
+	}
+	return &FindResult{Repos: repos, Errors: walkErrors, ExcludedDirs: excludedDirs, LimitReached: limitReached}, nil
+}
+
+// isSkippedBuildDir reports whether a directory name is one of the dependency or
+// build output directories that are never scanned for repositories.
+func isSkippedBuildDir(name string) bool {
+	return name == "vendor" || name == "node_modules" || name == "target" || name == "build"
+}
+
+// matchesExcludeDir reports whether the directory path equals or lies below one
+// of the excluded paths, or matches one of them as a glob pattern.
+func matchesExcludeDir(path string, excludeDirs []string) bool {
+	for _, exclude := range excludeDirs {
+		exclude = filepath.Clean(exclude)
+		if path == exclude || strings.HasPrefix(path, exclude+string(filepath.Separator)) {
+			return true
+		}
+		if ok, _ := filepath.Match(exclude, path); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// IsBareRepo reports whether dir is a bare repository: a repository without a
+// working tree, whose HEAD, objects and refs live directly in dir (e.g. project.git).
+func IsBareRepo(dir string) bool {
+	if filepath.Base(dir) == ".git" {
+		return false // the git directory of a regular repository
+	}
+	head, err := os.Stat(filepath.Join(dir, "HEAD"))
+	if err != nil || head.IsDir() {
+		return false
+	}
+	for _, sub := range []string{"objects", "refs"} {
+		info, err := os.Stat(filepath.Join(dir, sub))
+		if err != nil || !info.IsDir() {
+			return false
+		}
+	}
+	return true
+}
+
+// excludeSubmodules removes every repository whose closest discovered ancestor
+// lists it as a submodule path in its .gitmodules file.
+func excludeSubmodules(repos []string) []string {
+	known := make(map[string]bool, len(repos))
+	for _, repoPath := range repos {
+		known[repoPath] = true
+	}
+	submodulePaths := make(map[string]map[string]bool) // parent repo -> submodule paths, loaded lazily
+
+	var kept []string
+	for _, repoPath := range repos {
+		isSubmodule := false
+		for parent := filepath.Dir(repoPath); parent != filepath.Dir(parent); parent = filepath.Dir(parent) {
+			if !known[parent] {
+				continue
+			}
+			paths, ok := submodulePaths[parent]
+			if !ok {
+				paths = readSubmodulePaths(parent)
+				submodulePaths[parent] = paths
+			}
+			relPath, _ := filepath.Rel(parent, repoPath)
+			isSubmodule = paths[filepath.ToSlash(relPath)]
+			break // only the closest enclosing repository can own the submodule
+		}
+		if !isSubmodule {
+			kept = append(kept, repoPath)
+		}
+	}
+	return kept
+}
+
+// readSubmodulePaths returns the 'path = ...' entries of repoPath/.gitmodules.
+// A missing or unreadable file yields an empty set.
+func readSubmodulePaths(repoPath string) map[string]bool {
+	paths := make(map[string]bool)
+	f, err := os.Open(filepath.Join(repoPath, ".gitmodules"))
+	if err != nil {
+		return paths
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, found := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if found && strings.TrimSpace(key) == "path" {
+			paths[strings.TrimSuffix(strings.TrimSpace(value), "/")] = true
+		}
+	}
+	return paths
+}
@@ -0,0 +1,222 @@
+package gitops
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// makeTree creates the given paths under root: ".git/" is a repository's git
+// directory, ".git" without the slash a gitfile (as in worktrees and
+// submodules), "<name>.git/" a bare repository, any other path ending in "/"
+// a plain directory and anything else a file.
+func makeTree(tb testing.TB, root string, paths ...string) {
+	tb.Helper()
+	for _, p := range paths {
+		full := filepath.Join(root, filepath.FromSlash(p))
+		var err error
+		switch {
+		case filepath.Base(p) == ".git" && p[len(p)-1] != '/':
+			err = writeFile(full, "gitdir: /elsewhere\n")
+		case filepath.Ext(filepath.Clean(full)) == ".git" && filepath.Base(filepath.Clean(full)) != ".git":
+			for _, sub := range []string{"objects", "refs"} {
+				if err == nil {
+					err = os.MkdirAll(filepath.Join(full, sub), 0o755)
+				}
+			}
+			if err == nil {
+				err = writeFile(filepath.Join(full, "HEAD"), "ref: refs/heads/main\n")
+			}
+		case p[len(p)-1] == '/':
+			err = os.MkdirAll(full, 0o755)
+		default:
+			err = writeFile(full, p)
+		}
+		if err != nil {
+			tb.Fatal(err)
+		}
+	}
+}
+
+func writeFile(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(content), 0o644)
+}
+
+// discoveryTree is a tree exercising every rule of the walk: nested
+// repositories, gitfiles, bare repositories, skipped build directories,
+// .gitutilignore and names that sort differently per path element
+// ("a/b" before "a-c").
+var discoveryTree = []string{
+	"a/.git/",
+	"a/b/.git/",
+	"a/b/c/.git/",
+	"a-c/.git/",
+	"a.d/.git/",
+	"team/api/.git/",
+	"team/web/.git",
+	"team/node_modules/dep/.git/",
+	"team/vendor/lib/.git/",
+	"servers/srv.git/",
+	"servers/srv.git/hooks/",
+	"deep/x/y/z/.git/",
+	"ignored/one/.git/",
+	"scratch-1/.git/",
+	"scratch-keep/.git/",
+	"archive/old/.git/",
+	"empty/",
+	"README.md",
+}
+
+const discoveryIgnore = "ignored/\n**/scratch-*\n!scratch-keep\n"
+
+func TestFindGitReposParallelMatchesSerial(t *testing.T) {
+	root := t.TempDir()
+	makeTree(t, root, discoveryTree...)
+	if err := os.WriteFile(filepath.Join(root, IgnoreFileName), []byte(discoveryIgnore), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	serial, err := FindGitReposWithOptions(root, FindOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rel []string
+	for _, repo := range serial.Repos {
+		r, _ := filepath.Rel(root, repo)
+		rel = append(rel, filepath.ToSlash(r))
+	}
+	want := []string{"a", "a/b", "a/b/c", "a-c", "a.d", "archive/old", "deep/x/y/z", "scratch-keep", "servers/srv.git", "team/api", "team/web"}
+	if !reflect.DeepEqual(rel, want) {
+		t.Fatalf("serial walk found\n  %v\nwant\n  %v", rel, want)
+	}
+
+	tests := []struct {
+		name string
+		opts FindOptions
+	}{
+		{name: "defaults"},
+		{name: "exclude dir path", opts: FindOptions{ExcludeDirs: []string{filepath.Join(root, "team")}}},
+		{name: "exclude dir glob", opts: FindOptions{ExcludeDirs: []string{filepath.Join(root, "a", "*")}}},
+		{name: "limit not reached", opts: FindOptions{MaxRepos: len(want)}},
+		{name: "limit reached", opts: FindOptions{MaxRepos: len(want) - 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serial, err := FindGitReposWithOptions(root, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, workers := range []int{2, 8} {
+				opts := tt.opts
+				opts.Parallel = workers
+				parallel, err := FindGitReposWithOptions(root, opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if parallel.LimitReached != serial.LimitReached || parallel.ExcludedDirs != serial.ExcludedDirs {
+					t.Errorf("Parallel=%d: LimitReached=%v ExcludedDirs=%d, serial has %v and %d",
+						workers, parallel.LimitReached, parallel.ExcludedDirs, serial.LimitReached, serial.ExcludedDirs)
+				}
+				if serial.LimitReached {
+					// Which repositories are kept depends on timing; only the count is fixed.
+					if len(parallel.Repos) != len(serial.Repos) {
+						t.Errorf("Parallel=%d: found %d repos, serial %d", workers, len(parallel.Repos), len(serial.Repos))
+					}
+					continue
+				}
+				if !reflect.DeepEqual(parallel.Repos, serial.Repos) {
+					t.Errorf("Parallel=%d found\n  %v\nserial\n  %v", workers, parallel.Repos, serial.Repos)
+				}
+			}
+		})
+	}
+}
+
+func TestFindGitReposMaxRepos(t *testing.T) {
+	root := t.TempDir()
+	makeTree(t, root, "one/.git/", "two/.git/", "three/.git/")
+
+	tests := []struct {
+		maxRepos         int
+		wantRepos        int
+		wantLimitReached bool
+	}{
+		{maxRepos: 0, wantRepos: 3},
+		{maxRepos: 4, wantRepos: 3},
+		{maxRepos: 3, wantRepos: 3}, // exactly at the limit: complete
+		{maxRepos: 2, wantRepos: 2, wantLimitReached: true},
+		{maxRepos: 1, wantRepos: 1, wantLimitReached: true},
+	}
+	for _, tt := range tests {
+		for _, workers := range []int{0, 4} {
+			t.Run(fmt.Sprintf("max=%d/parallel=%d", tt.maxRepos, workers), func(t *testing.T) {
+				result, err := FindGitReposWithOptions(root, FindOptions{MaxRepos: tt.maxRepos, Parallel: workers})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(result.Repos) != tt.wantRepos || result.LimitReached != tt.wantLimitReached {
+					t.Errorf("found %d repos, LimitReached=%v; want %d, %v",
+						len(result.Repos), result.LimitReached, tt.wantRepos, tt.wantLimitReached)
+				}
+			})
+		}
+	}
+}
+
+func TestWalkOrderLess(t *testing.T) {
+	sep := string(filepath.Separator)
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"a" + sep + "b", "a-c", true},
+		{"a-c", "a" + sep + "b", false},
+		{"a", "a" + sep + "b", true},
+		{"a" + sep + "b", "a", false},
+		{"x", "x", false},
+	}
+	for _, tt := range tests {
+		if got := walkOrderLess(tt.a, tt.b); got != tt.want {
+			t.Errorf("walkOrderLess(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// benchmarkTree generates a workspace of 20 teams with 25 repositories each,
+// every repository holding a few levels of ordinary directories, to compare
+// the serial and parallel walks.
+func benchmarkTree(b *testing.B) string {
+	b.Helper()
+	root := b.TempDir()
+	var paths []string
+	for team := 0; team < 20; team++ {
+		for repo := 0; repo < 25; repo++ {
+			base := fmt.Sprintf("team%02d/repo%02d/", team, repo)
+			paths = append(paths, base+".git/", base+"src/pkg/internal/", base+"docs/", base+"node_modules/dep/")
+		}
+	}
+	makeTree(b, root, paths...)
+	return root
+}
+
+func benchmarkFindGitRepos(b *testing.B, parallel int) {
+	root := benchmarkTree(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		result, err := FindGitReposWithOptions(root, FindOptions{Parallel: parallel})
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(result.Repos) != 500 {
+			b.Fatalf("found %d repos, want 500", len(result.Repos))
+		}
+	}
+}
+
+func BenchmarkFindGitReposSerial(b *testing.B)   { benchmarkFindGitRepos(b, 0) }
+func BenchmarkFindGitReposParallel(b *testing.B) { benchmarkFindGitRepos(b, 8) }
@@ -2,12 +2,10 @@
 package gitops 
 
 import (
-	"bytes"
+	"context"
 	"errors"
 	"fmt"
-	"os"
-	"os/exec"
-	"path/filepath"
+	"strconv"
 	"strings"
 )
 
@@ -16,54 +14,261 @@ import (
 
 // RunGitCommand executes a git command and returns its trimmed stdout output or an error
 // including stderr content for better diagnostics.
+// The command is executed by DefaultRunner, which tests can replace with a fake.
 func RunGitCommand(args ...string) (string, error) { // function to run git commands take array of strings as input and return the output or error uses valadic operator
-	cmd := exec.Command("git", args...) //uses exec commnad to make an object and store upack args
-	var stdout, stderr bytes.Buffer // variables stdout and stderr of type bytes.buffer to store output and error if any 
-	cmd.Stdout = &stdout //  address of stdout (which is a bytes.Buffer) to cmd.Stdout.
-	cmd.Stderr = &stderr //  address of stderr (which is a bytes.Buffer) to cmd.Stderr.
-	err := cmd.Run() // returns error to err if any
-	output := strings.TrimSpace(stdout.String()) // triming whitespaces or new lines 
-	if err != nil { // conditional statement to check if err is null or not
-		return output, fmt.Errorf("command 'git %s' failed: %w\nStderr: %s", strings.Join(args, " "), err, stderr.String()) // print error
-	}
-	return output, nil // return output and error as nill because there is no error if compiler reached here.
+	return RunGitCommandContext(baseContext, args...)
+}
+
+// RunGitCommandContext is RunGitCommand, stopping git if ctx is cancelled.
+func RunGitCommandContext(ctx context.Context, args ...string) (string, error) {
+	return runContext(ctx, DefaultRunner, args...)
 }
 
 // DetectDefaultMainBranch tries to find 'main' or 'master' branch in the current repository
 // by calling the exported RunGitCommand function.
 func DetectDefaultMainBranch() (string, error) { // return type of string and error string: main or master
-	_, errMain := RunGitCommand("show-ref", "--verify", "--quiet", "refs/heads/main") // Checks for main branch
+	return DetectDefaultMainBranchIn("")
+}
+
+// DetectDefaultMainBranchIn is DetectDefaultMainBranch for the repository at repoPath.
+// An empty repoPath means the current directory. When neither local 'main' nor
+// 'master' exists, the branch referenced by refs/remotes/origin/HEAD is used; if
+// that branch has no local counterpart the remote-tracking name ("origin/<branch>")
+// is returned. The result is cached per repository for the rest of the run.
+func DetectDefaultMainBranchIn(repoPath string) (string, error) {
+	if v, ok := cache.getDefaultBranch(repoPath); ok {
+		return v.name, v.err
+	}
+	name, err := detectDefaultMainBranch(repoPath)
+	cache.setDefaultBranch(repoPath, cachedBranch{name: name, err: err})
+	return name, err
+}
+
+// detectDefaultMainBranch does the uncached work of DetectDefaultMainBranchIn.
+func detectDefaultMainBranch(repoPath string) (string, error) {
+	_, errMain := RunGitCommand("-C", repoPath, "show-ref", "--verify", "--quiet", "refs/heads/main") // Checks for main branch
 	if errMain == nil { // is this is nill means no error main exits : lets gooooo
 		return "main", nil // main branch exits !
 	}
-	_, errMaster := RunGitCommand("show-ref", "--verify", "--quiet", "refs/heads/master") // Checks for master branch same as main branch
+	_, errMaster := RunGitCommand("-C", repoPath, "show-ref", "--verify", "--quiet", "refs/heads/master") // Checks for master branch same as main branch
 	if errMaster == nil {
 		return "master", nil
 	}
-	return "", errors.New("neither 'main' nor 'master' branch found. Please specify with --main flag")
+	// Fall back to the branch origin's HEAD points at (e.g. trunk or develop),
+	// as recorded locally by clone or 'git remote set-head'.
+	if remoteHead, err := RunGitCommand("-C", repoPath, "symbolic-ref", "--short", "refs/remotes/origin/HEAD"); err == nil && remoteHead != "" {
+		localName := strings.TrimPrefix(remoteHead, "origin/")
+		if _, err := RunGitCommand("-C", repoPath, "show-ref", "--verify", "--quiet", "refs/heads/"+localName); err == nil {
+			return localName, nil
+		}
+		return remoteHead, nil // only the remote-tracking branch exists, e.g. "origin/trunk"
+	}
+	return "", errors.New("neither 'main' nor 'master' branch found and origin/HEAD is not set (try 'git remote set-head origin --auto'). Please specify with --main flag")
+}
+
+// CurrentBranch returns the name of the branch checked out in the repository at repoPath.
+// A detached HEAD is reported as "HEAD".
+func CurrentBranch(repoPath string) (string, error) {
+	return RunGitCommand("-C", repoPath, "rev-parse", "--abbrev-ref", "HEAD")
+}
+
+// IsDirty reports whether the repository at repoPath has uncommitted changes
+// or untracked files, based on 'git status --porcelain'.
+func IsDirty(repoPath string) (bool, error) {
+	statusOutput, err := RunGitCommand("-C", repoPath, "status", "--porcelain=v1")
+	if err != nil {
+		return false, err
+	}
+	return statusOutput != "", nil
+}
+
+// HasTrackedChanges is IsDirty without untracked files: it reports whether
+// tracked files in the repository at repoPath have uncommitted changes.
+func HasTrackedChanges(repoPath string) (bool, error) {
+	statusOutput, err := RunGitCommand("-C", repoPath, "status", "--porcelain=v1", "--untracked-files=no")
+	if err != nil {
+		return false, err
+	}
+	return statusOutput != "", nil
 }
 
-// FindGitRepos walks the directory tree starting from rootDir and finds paths
-// containing a .git subdirectory, indicating a Git repository root.
-func FindGitRepos(rootDir string) ([]string, error) { //takes input rootDir and returns path to .git
-	var repos []string // take as empty string slice
-	err := filepath.WalkDir(rootDir, func(path string, d os.DirEntry, err error) error { // using filepath.WalkDir, walks and visits every file/directory
-		if err != nil { // no error
-			fmt.Fprintf(os.Stderr, "Warning: Error accessing path %q: %v\n", path, err)
-			return filepath.SkipDir
+// CommitAuthors returns the author email of every commit reachable from HEAD
+// in the repository at repoPath that is newer than since (any date format
+// accepted by 'git log --since'). A repository without commits yields no authors.
+func CommitAuthors(repoPath, since string) ([]string, error) {
+	output, err := RunGitCommand("-C", repoPath, "log", "--since="+since, "--pretty=format:%ae")
+	if err != nil {
+		if strings.Contains(err.Error(), "does not have any commits yet") {
+			return nil, nil
 		}
-		if d.IsDir() && d.Name() == ".git" {
-			repoPath := filepath.Dir(path)
-			repos = append(repos, repoPath)
-			return filepath.SkipDir
+		return nil, err
+	}
+	if output == "" {
+		return nil, nil
+	}
+	return strings.Split(output, "\n"), nil
+}
+
+// RemoteURL returns the fetch URL of the named remote in the repository at repoPath.
+func RemoteURL(repoPath, remote string) (string, error) {
+	return RunGitCommand("-C", repoPath, "remote", "get-url", remote)
+}
+
+// LastCommit returns the relative commit date (e.g. "3 days ago") and subject of
+// HEAD in the repository at repoPath.
+func LastCommit(repoPath string) (relative, subject string, err error) {
+	output, err := RunGitCommand("-C", repoPath, "log", "-1", "--format=%cr%x00%s")
+	if err != nil {
+		return "", "", err
+	}
+	relative, subject, _ = strings.Cut(output, "\x00")
+	return relative, subject, nil
+}
+
+// BranchAge returns the relative date of the last commit on the local branch
+// in the repository at repoPath, e.g. "2 months ago".
+func BranchAge(repoPath, branch string) (string, error) {
+	return RunGitCommand("-C", repoPath, "log", "-1", "--format=%cr", "refs/heads/"+branch, "--")
+}
+
+// StashCount returns the number of entries in 'git stash list' of the
+// repository at repoPath.
+func StashCount(repoPath string) (int, error) {
+	output, err := RunGitCommand("-C", repoPath, "stash", "list")
+	if err != nil || output == "" {
+		return 0, err
+	}
+	return strings.Count(output, "\n") + 1, nil
+}
+
+// Remotes returns the names of the remotes configured in the repository at repoPath.
+func Remotes(repoPath string) ([]string, error) {
+	output, err := RunGitCommand("-C", repoPath, "remote")
+	if err != nil || output == "" {
+		return nil, err
+	}
+	return strings.Split(output, "\n"), nil
+}
+
+// RemoteDefaultBranch returns the remote-tracking name of the default branch of
+// the named remote in the repository at repoPath, e.g. "upstream/main": the
+// branch refs/remotes/<remote>/HEAD points at, or else <remote>/main or
+// <remote>/master if fetched.
+func RemoteDefaultBranch(repoPath, remote string) (string, error) {
+	remotes, err := Remotes(repoPath)
+	if err != nil {
+		return "", err
+	}
+	found := false
+	for _, r := range remotes {
+		if r == remote {
+			found = true
+			break
 		}
-		if d.IsDir() && (d.Name() == "vendor" || d.Name() == "node_modules" || d.Name() == "target" || d.Name() == "build") {
-			return filepath.SkipDir
+	}
+	if !found {
+		return "", fmt.Errorf("no remote named '%s'", remote)
+	}
+	if head, err := RunGitCommand("-C", repoPath, "symbolic-ref", "--short", "refs/remotes/"+remote+"/HEAD"); err == nil && head != "" {
+		return head, nil
+	}
+	for _, name := range []string{"main", "master"} {
+		if RefExists(repoPath, "refs/remotes/"+remote+"/"+name) {
+			return remote + "/" + name, nil
 		}
-		return nil
-	})
+	}
+	return "", fmt.Errorf("default branch of remote '%s' is unknown (fetch it, or run 'git remote set-head %s --auto')", remote, remote)
+}
+
+// TipAuthor returns the author name and email of the commit at the tip of ref
+// in the repository at repoPath.
+func TipAuthor(repoPath, ref string) (name, email string, err error) {
+	output, err := RunGitCommand("-C", repoPath, "log", "-1", "--format=%an%x00%ae", ref, "--")
+	if err != nil {
+		return "", "", err
+	}
+	name, email, _ = strings.Cut(output, "\x00")
+	return name, email, nil
+}
+
+// ResolveCommit returns the SHA of the commit ref points at in the repository
+// at repoPath, e.g. for "HEAD" or "@{u}". It fails if ref does not resolve.
+func ResolveCommit(repoPath, ref string) (string, error) {
+	return RunGitCommand("-C", repoPath, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+}
+
+// CountCommits returns the number of commits reachable from to but not from
+// from ('git rev-list --count from..to') in the repository at repoPath.
+func CountCommits(repoPath, from, to string) (int, error) {
+	output, err := RunGitCommand("-C", repoPath, "rev-list", "--count", from+".."+to)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(output)
+}
+
+// ErrNoMergeBase is returned by MergeBase when the two commits have no common history.
+var ErrNoMergeBase = errors.New("no common history")
+
+// MergeBase returns the SHA of the best common ancestor of a and b
+// ('git merge-base a b') in the repository at repoPath, or ErrNoMergeBase if
+// the two have no common history.
+func MergeBase(repoPath, a, b string) (string, error) {
+	output, err := RunGitCommand("-C", repoPath, "merge-base", a, b)
+	if err != nil && exitCode(err) == 1 && output == "" {
+		// 'git merge-base' exits 1 without output when there is no common ancestor.
+		return "", ErrNoMergeBase
+	}
+	return output, err
+}
+
+// CommitDate returns the committer date of rev in the repository at repoPath,
+// both relative (e.g. "3 weeks ago") and in strict ISO 8601 format.
+func CommitDate(repoPath, rev string) (relative, iso string, err error) {
+	output, err := RunGitCommand("-C", repoPath, "log", "-1", "--format=%cr%x00%cI", rev, "--")
 	if err != nil {
+		return "", "", err
+	}
+	relative, iso, _ = strings.Cut(output, "\x00")
+	return relative, iso, nil
+}
+
+// LocalBranch is a local branch and the upstream it tracks ("" if none).
+type LocalBranch struct {
+	Name     string
+	Upstream string
+}
+
+// LocalBranches lists the local branches of the repository at repoPath, sorted by name.
+func LocalBranches(repoPath string) ([]LocalBranch, error) {
+	output, err := RunGitCommand("-C", repoPath, "for-each-ref", "--format=%(refname:short)%00%(upstream:short)", "refs/heads")
+	if err != nil {
+		return nil, err
+	}
+	var branches []LocalBranch
+	for _, line := range strings.Split(output, "\n") {
+		if line == "" {
+			continue
+		}
+		name, upstream, _ := strings.Cut(line, "\x00")
+		branches = append(branches, LocalBranch{Name: name, Upstream: upstream})
+	}
+	return branches, nil
+}
+
+// RefExists reports whether the fully qualified ref (e.g. "refs/remotes/origin/main")
+// exists in the repository at repoPath.
+func RefExists(repoPath, ref string) bool {
+	_, err := RunGitCommand("-C", repoPath, "show-ref", "--verify", "--quiet", ref)
+	return err == nil
+}
+
+// MergedBranches returns the local branches of the repository at repoPath whose
+// tips are reachable from target, including target itself if it is local.
+func MergedBranches(repoPath, target string) ([]string, error) {
+	output, err := RunGitCommand("-C", repoPath, "branch", "--merged", target, "--format=%(refname:short)")
+	if err != nil || output == "" {
 		return nil, err
 	}
-	return repos, nil
+	return strings.Split(output, "\n"), nil
 }
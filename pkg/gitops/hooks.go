@@ -0,0 +1,93 @@
+package gitops
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// HookPath returns the path git runs the named hook (e.g. "pre-commit") from
+// in the repository at repoPath. It honours core.hooksPath, so the hook may
+// live outside the repository's git directory.
+func HookPath(repoPath, hook string) (string, error) {
+	path, err := RunGitCommand("-C", repoPath, "rev-parse", "--git-path", "hooks/"+hook)
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(repoPath, path)
+	}
+	return path, nil
+}
+
+// Values returned by HookState.
+const (
+	HookMissing   = "missing"
+	HookInstalled = "installed" // present; only reported when there is no script to compare with
+	HookMatches   = "matches"   // executable, with the same content as the script
+	HookDifferent = "different" // present, but with other content or not executable
+)
+
+// HookState reports whether a hook is installed at hookPath and, if script is
+// not empty, whether it matches that script. Symlinks are followed, so a hook
+// linked to the script matches it.
+func HookState(hookPath, script string) (string, error) {
+	info, err := os.Stat(hookPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return HookMissing, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if script == "" {
+		return HookInstalled, nil
+	}
+	if info.Mode().Perm()&0o111 == 0 {
+		return HookDifferent, nil
+	}
+	installed, err := os.ReadFile(hookPath)
+	if err != nil {
+		return "", err
+	}
+	want, err := os.ReadFile(script)
+	if err != nil {
+		return "", err
+	}
+	if !bytes.Equal(installed, want) {
+		return HookDifferent, nil
+	}
+	return HookMatches, nil
+}
+
+// InstallHook installs script as the hook at hookPath, replacing any hook
+// already there, either as an executable copy or (with symlink) as a symbolic
+// link to the script, which is then made executable itself.
+func InstallHook(hookPath, script string, symlink bool) error {
+	if err := os.MkdirAll(filepath.Dir(hookPath), 0o755); err != nil {
+		return err
+	}
+	if err := os.Remove(hookPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove existing hook: %w", err)
+	}
+	if symlink {
+		if err := os.Symlink(script, hookPath); err != nil {
+			return err
+		}
+		info, err := os.Stat(script)
+		if err != nil {
+			return err
+		}
+		return os.Chmod(script, info.Mode().Perm()|0o111)
+	}
+	data, err := os.ReadFile(script)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(hookPath, data, 0o755); err != nil {
+		return err
+	}
+	// WriteFile's mode is subject to the umask; hooks must be executable regardless.
+	return os.Chmod(hookPath, 0o755)
+}
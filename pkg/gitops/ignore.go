@@ -0,0 +1,140 @@
+package gitops
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// IgnoreFileName is the name of the file, placed at the scan root, that lists
+// repository paths FindGitRepos should skip.
+const IgnoreFileName = ".gitutilignore"
+
+// ignorePattern is a single compiled line of a .gitutilignore file.
+type ignorePattern struct {
+	re       *regexp.Regexp
+	negate   bool
+	anchored bool // pattern contains a '/', so it is matched against the whole relative path
+}
+
+// IgnoreRules holds the patterns loaded from a .gitutilignore file.
+// The zero value ignores nothing.
+type IgnoreRules struct {
+	patterns []ignorePattern
+}
+
+// LoadIgnoreFile reads rootDir/.gitutilignore. A missing file is not an error
+// and yields empty rules.
+func LoadIgnoreFile(rootDir string) (*IgnoreRules, error) {
+	f, err := os.Open(filepath.Join(rootDir, IgnoreFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &IgnoreRules{}, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", IgnoreFileName, err)
+	}
+	defer f.Close()
+
+	rules := &IgnoreRules{}
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p, err := compileIgnorePattern(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid pattern %q: %w", IgnoreFileName, lineNo, line, err)
+		}
+		rules.patterns = append(rules.patterns, p)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", IgnoreFileName, err)
+	}
+	return rules, nil
+}
+
+// compileIgnorePattern converts one gitignore-style line into a regular expression.
+// Supported syntax: '*', '?', '[...]', '**', a leading '!' to re-include and a
+// leading or inner '/' to anchor the pattern to the scan root.
+func compileIgnorePattern(line string) (ignorePattern, error) {
+	var p ignorePattern
+	if strings.HasPrefix(line, "!") {
+		p.negate = true
+		line = line[1:]
+	}
+	line = strings.TrimSuffix(line, "/") // only directories are ever matched
+	if strings.Contains(line, "/") {
+		p.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case strings.HasPrefix(line[i:], "**/"):
+			sb.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(line[i:], "/**"):
+			sb.WriteString("(/.*)?")
+			i += 2
+		case strings.HasPrefix(line[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(line[i:], ']')
+			if end < 0 {
+				return p, errors.New("unterminated character class")
+			}
+			class := line[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + class + "]")
+			i += end
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+
+	re, err := regexp.Compile(sb.String())
+	if err != nil {
+		return p, err
+	}
+	p.re = re
+	return p, nil
+}
+
+// Match reports whether the directory at relPath (relative to the scan root,
+// using '/' or the OS separator) is excluded. As with .gitignore, the last
+// matching pattern wins.
+func (r *IgnoreRules) Match(relPath string) bool {
+	if r == nil || len(r.patterns) == 0 {
+		return false
+	}
+	relPath = filepath.ToSlash(relPath)
+	ignored := false
+	for _, p := range r.patterns {
+		target := relPath
+		if !p.anchored {
+			target = relPath[strings.LastIndex(relPath, "/")+1:]
+		}
+		if p.re.MatchString(target) {
+			ignored = !p.negate
+		}
+	}
+	return ignored
+}
@@ -0,0 +1,172 @@
+package gitops
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// parallelWalk is the FindOptions.Parallel version of the walk in
+// FindGitReposWithOptions. Up to FindOptions.Parallel directories are read at
+// the same time, which hides the latency of each stat and readdir on network filesystems.
+// It applies the same rules as the serial walk: .gitutilignore, ExcludeDirs,
+// .git files and directories, bare repositories and the skipped build
+// directories. Repositories and errors are sorted into the order the serial
+// walk reports them in.
+type parallelWalk struct {
+	rootDir string
+	ignore  *IgnoreRules
+	opts    FindOptions
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	queue   []string // directories waiting to be read
+	pending int      // directories queued or being read
+	errors  []WalkError
+
+	excludedDirs atomic.Int64
+	stopped      atomic.Bool // a repository beyond MaxRepos was found: drop the remaining work
+	found        chan string
+}
+
+// walkParallel walks rootDir with opts.Parallel workers and returns what the
+// serial walk would. With MaxRepos, the walk stops when a repository beyond
+// that many is found; which ones are kept then depends on timing, but the
+// result is still sorted and never longer than MaxRepos.
+func walkParallel(rootDir string, ignore *IgnoreRules, opts FindOptions) *FindResult {
+	info, err := os.Lstat(rootDir)
+	if err != nil {
+		return &FindResult{Errors: []WalkError{{Path: rootDir, Err: err}}}
+	}
+	w := &parallelWalk{rootDir: rootDir, ignore: ignore, opts: opts, found: make(chan string, 64)}
+	w.cond = sync.NewCond(&w.mu)
+
+	var repos []string
+	collected := make(chan struct{})
+	go func() {
+		defer close(collected)
+		for repoPath := range w.found {
+			if opts.MaxRepos > 0 && len(repos) >= opts.MaxRepos {
+				w.stopped.Store(true)
+				continue
+			}
+			repos = append(repos, repoPath)
+		}
+	}()
+
+	// The root is handled like any other directory, except that it is never
+	// skipped by .gitutilignore or ExcludeDirs.
+	if w.visitDir(rootDir, filepath.Base(rootDir), info.IsDir()) {
+		w.pending = 1
+		w.queue = append(w.queue, rootDir)
+		var workers sync.WaitGroup
+		for range opts.Parallel {
+			workers.Add(1)
+			go func() {
+				defer workers.Done()
+				w.work()
+			}()
+		}
+		workers.Wait()
+	}
+	close(w.found)
+	<-collected
+
+	sort.Slice(repos, func(i, j int) bool { return walkOrderLess(repos[i], repos[j]) })
+	sort.Slice(w.errors, func(i, j int) bool { return walkOrderLess(w.errors[i].Path, w.errors[j].Path) })
+	return &FindResult{
+		Repos:        repos,
+		Errors:       w.errors,
+		ExcludedDirs: int(w.excludedDirs.Load()),
+		LimitReached: w.stopped.Load(),
+	}
+}
+
+// work reads queued directories until there are none left anywhere.
+func (w *parallelWalk) work() {
+	for {
+		w.mu.Lock()
+		for len(w.queue) == 0 && w.pending > 0 {
+			w.cond.Wait()
+		}
+		if w.pending == 0 {
+			w.mu.Unlock()
+			return
+		}
+		dir := w.queue[len(w.queue)-1]
+		w.queue = w.queue[:len(w.queue)-1]
+		w.mu.Unlock()
+
+		subdirs := w.readDir(dir)
+
+		w.mu.Lock()
+		w.queue = append(w.queue, subdirs...)
+		w.pending += len(subdirs) - 1
+		w.cond.Broadcast()
+		w.mu.Unlock()
+	}
+}
+
+// readDir lists dir, reports the repositories it holds and returns the
+// subdirectories to descend into.
+func (w *parallelWalk) readDir(dir string) []string {
+	if w.stopped.Load() {
+		return nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		w.mu.Lock()
+		w.errors = append(w.errors, WalkError{Path: dir, Err: err})
+		w.mu.Unlock()
+	}
+	var subdirs []string
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if entry.IsDir() {
+			if relPath, relErr := filepath.Rel(w.rootDir, path); relErr == nil && w.ignore.Match(relPath) {
+				continue
+			}
+			if matchesExcludeDir(path, w.opts.ExcludeDirs) {
+				w.excludedDirs.Add(1)
+				continue
+			}
+		}
+		if w.visitDir(path, entry.Name(), entry.IsDir()) {
+			subdirs = append(subdirs, path)
+		}
+	}
+	return subdirs
+}
+
+// visitDir reports a repository found at path and whether the walk should
+// descend into it, following the rules of the serial walk.
+func (w *parallelWalk) visitDir(path, name string, isDir bool) bool {
+	if name == ".git" {
+		w.found <- filepath.Dir(path)
+		return false
+	}
+	if !isDir {
+		return false
+	}
+	if IsBareRepo(path) {
+		w.found <- path
+		return false
+	}
+	return !isSkippedBuildDir(name)
+}
+
+// walkOrderLess orders paths the way filepath.WalkDir visits them: element by
+// element, so "a/b" comes before "a-c" although '-' sorts before '/'.
+func walkOrderLess(a, b string) bool {
+	as := strings.Split(a, string(filepath.Separator))
+	bs := strings.Split(b, string(filepath.Separator))
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if as[i] != bs[i] {
+			return as[i] < bs[i]
+		}
+	}
+	return len(as) < len(bs)
+}
@@ -0,0 +1,76 @@
+package gitops
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PorcelainStatus is the parsed output of 'git status --porcelain=v2 --branch':
+// the branch, its upstream and the working tree state from a single git call.
+type PorcelainStatus struct {
+	// Branch is the current branch, or "HEAD" when detached, matching
+	// 'git rev-parse --abbrev-ref HEAD'.
+	Branch string
+	// Upstream is the configured upstream, e.g. "origin/main", or "".
+	Upstream string
+	// HasAheadBehind is false when there is no upstream, or it is configured
+	// but its remote-tracking branch does not exist (e.g. deleted on the remote).
+	HasAheadBehind bool
+	Ahead          int
+	Behind         int
+	// Changed counts changed, renamed and unmerged entries; Untracked counts
+	// untracked files (none when git was run with --untracked-files=no).
+	Changed   int
+	Untracked int
+}
+
+// Dirty reports whether there is anything to commit, untracked files included.
+func (s PorcelainStatus) Dirty() bool {
+	return s.Changed > 0 || s.Untracked > 0
+}
+
+// ParsePorcelainV2 parses the output of 'git status --porcelain=v2 --branch'.
+// Unknown header lines (e.g. '# stash' from newer git versions) are ignored.
+func ParsePorcelainV2(output string) (PorcelainStatus, error) {
+	var s PorcelainStatus
+	for _, line := range strings.Split(output, "\n") {
+		if line == "" {
+			continue
+		}
+		if header, ok := strings.CutPrefix(line, "# "); ok {
+			key, value, _ := strings.Cut(header, " ")
+			switch key {
+			case "branch.head":
+				s.Branch = value
+				if value == "(detached)" {
+					s.Branch = "HEAD"
+				}
+			case "branch.upstream":
+				s.Upstream = value
+			case "branch.ab":
+				// "+<ahead> -<behind>"
+				aheadText, behindText, _ := strings.Cut(value, " ")
+				ahead, errAhead := strconv.Atoi(strings.TrimPrefix(aheadText, "+"))
+				behind, errBehind := strconv.Atoi(strings.TrimPrefix(behindText, "-"))
+				if errAhead != nil || errBehind != nil {
+					return s, fmt.Errorf("unexpected porcelain v2 line %q", line)
+				}
+				s.HasAheadBehind = true
+				s.Ahead, s.Behind = ahead, behind
+			}
+			continue
+		}
+		switch line[0] {
+		case '1', '2', 'u':
+			s.Changed++
+		case '?':
+			s.Untracked++
+		case '!':
+			// ignored file, only listed with --ignored
+		default:
+			return s, fmt.Errorf("unexpected porcelain v2 line %q", line)
+		}
+	}
+	return s, nil
+}
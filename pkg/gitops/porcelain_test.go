@@ -0,0 +1,86 @@
+package gitops
+
+import "testing"
+
+func TestParsePorcelainV2(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    PorcelainStatus
+		wantErr bool
+	}{
+		{
+			name:   "clean with upstream",
+			output: "# branch.oid 1a2b3c\n# branch.head main\n# branch.upstream origin/main\n# branch.ab +0 -0",
+			want:   PorcelainStatus{Branch: "main", Upstream: "origin/main", HasAheadBehind: true},
+		},
+		{
+			name: "ahead, behind and changes",
+			output: "# branch.oid 1a2b3c\n# branch.head feature\n# branch.upstream origin/feature\n# branch.ab +2 -3\n" +
+				"1 .M N... 100644 100644 100644 1a2b3c 1a2b3c cmd/root.go\n" +
+				"2 R. N... 100644 100644 100644 1a2b3c 1a2b3c R100 new.go\told.go\n" +
+				"u UU N... 100644 100644 100644 100644 1a2b3c 1a2b3c 1a2b3c conflict.go\n" +
+				"? notes.txt\n? tmp/",
+			want: PorcelainStatus{Branch: "feature", Upstream: "origin/feature", HasAheadBehind: true,
+				Ahead: 2, Behind: 3, Changed: 3, Untracked: 2},
+		},
+		{
+			name:   "no upstream",
+			output: "# branch.oid 1a2b3c\n# branch.head topic",
+			want:   PorcelainStatus{Branch: "topic"},
+		},
+		{
+			name:   "upstream gone",
+			output: "# branch.oid 1a2b3c\n# branch.head topic\n# branch.upstream origin/topic",
+			want:   PorcelainStatus{Branch: "topic", Upstream: "origin/topic"},
+		},
+		{
+			name:   "detached HEAD",
+			output: "# branch.oid 1a2b3c\n# branch.head (detached)",
+			want:   PorcelainStatus{Branch: "HEAD"},
+		},
+		{
+			name:   "unknown headers and ignored files",
+			output: "# branch.oid (initial)\n# branch.head main\n# stash 2\n! build/out.o",
+			want:   PorcelainStatus{Branch: "main"},
+		},
+		{
+			name:    "malformed ahead/behind",
+			output:  "# branch.head main\n# branch.ab +x -1",
+			wantErr: true,
+		},
+		{
+			name:    "unknown entry",
+			output:  "# branch.head main\nZ what",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePorcelainV2(tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePorcelainV2() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("ParsePorcelainV2() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPorcelainStatusDirty(t *testing.T) {
+	tests := []struct {
+		status PorcelainStatus
+		want   bool
+	}{
+		{PorcelainStatus{}, false},
+		{PorcelainStatus{Changed: 1}, true},
+		{PorcelainStatus{Untracked: 1}, true},
+		{PorcelainStatus{Ahead: 1, Behind: 1}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.status.Dirty(); got != tt.want {
+			t.Errorf("%+v.Dirty() = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
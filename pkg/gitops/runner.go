@@ -0,0 +1,139 @@
+package gitops
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Runner executes git with the given arguments and returns its trimmed stdout.
+// On failure the returned error should describe the command and include stderr.
+type Runner interface {
+	Run(args ...string) (string, error)
+}
+
+// ContextRunner is a Runner that can stop a running command when its context
+// is cancelled. Runners that do not implement it only refuse to start commands
+// once the context is done.
+type ContextRunner interface {
+	Runner
+	RunContext(ctx context.Context, args ...string) (string, error)
+}
+
+// cancelWaitDelay is how long a cancelled git command gets to exit after being
+// interrupted before it is killed.
+const cancelWaitDelay = 3 * time.Second
+
+// GitBinEnv names the environment variable that overrides the git executable,
+// e.g. GIT_UTIL_GIT_BIN=/opt/git/bin/git.
+const GitBinEnv = "GIT_UTIL_GIT_BIN"
+
+// ErrGitNotFound is returned instead of the raw exec error when the git
+// executable cannot be found.
+var ErrGitNotFound = errors.New("git not found on PATH; install git or set " + GitBinEnv)
+
+// GitBinary returns the git executable to run: $GIT_UTIL_GIT_BIN if set,
+// otherwise "git" looked up on PATH.
+func GitBinary() string {
+	if bin := os.Getenv(GitBinEnv); bin != "" {
+		return bin
+	}
+	return "git"
+}
+
+// CheckGit reports whether the git executable can be run, so that a missing git
+// is reported once up front rather than as a failure in every repository.
+func CheckGit() error {
+	bin := GitBinary()
+	if _, err := exec.LookPath(bin); err != nil {
+		if bin != "git" {
+			return fmt.Errorf("%s=%s is not an executable git: %w", GitBinEnv, bin, err)
+		}
+		return ErrGitNotFound
+	}
+	return nil
+}
+
+// ExecRunner is the Runner that spawns the real git binary (see GitBinary).
+type ExecRunner struct {
+	// GlobalArgs are passed to git before the command's own arguments,
+	// e.g. {"--no-optional-locks"}.
+	GlobalArgs []string
+}
+
+// Run executes 'git <args...>' and returns its trimmed stdout output.
+func (r ExecRunner) Run(args ...string) (string, error) {
+	return r.RunContext(context.Background(), args...)
+}
+
+// RunContext is Run, stopping git when ctx is cancelled. git is sent an
+// interrupt first, as with Ctrl-C, so that it can remove its lock files.
+func (r ExecRunner) RunContext(ctx context.Context, args ...string) (string, error) {
+	if len(r.GlobalArgs) > 0 {
+		args = append(append([]string(nil), r.GlobalArgs...), args...)
+	}
+	cmd := exec.CommandContext(ctx, GitBinary(), args...) //uses exec commnad to make an object and store upack args
+	if ctx.Done() != nil {
+		cmd.Cancel = func() error { return cmd.Process.Signal(os.Interrupt) }
+		cmd.WaitDelay = cancelWaitDelay
+	}
+	var stdout, stderr bytes.Buffer // variables stdout and stderr of type bytes.buffer to store output and error if any
+	cmd.Stdout = &stdout            //  address of stdout (which is a bytes.Buffer) to cmd.Stdout.
+	cmd.Stderr = &stderr            //  address of stderr (which is a bytes.Buffer) to cmd.Stderr.
+	err := cmd.Run()                // returns error to err if any
+	output := strings.TrimSpace(stdout.String())
+	var execErr *exec.Error
+	if errors.As(err, &execErr) {
+		return output, ErrGitNotFound // git could not be started at all; the arguments do not matter
+	}
+	// Errors end up on the terminal and in reports, so credentials in URLs are masked.
+	if err != nil && ctx.Err() != nil {
+		return output, fmt.Errorf("command 'git %s' cancelled: %w", strings.Join(redactArgs(args), " "), ctx.Err())
+	}
+	if err != nil {
+		return output, fmt.Errorf("command 'git %s' failed: %w\nStderr: %s", strings.Join(redactArgs(args), " "), err, RedactURL(stderr.String()))
+	}
+	return output, nil
+}
+
+// DefaultRunner executes every git command issued through RunGitCommand, and
+// therefore by every helper in this package and every git-util command.
+var DefaultRunner Runner = ExecRunner{}
+
+// baseContext is the context of every command issued through RunGitCommand.
+var baseContext = context.Background()
+
+// SetContext makes every later RunGitCommand call stop when ctx is cancelled,
+// e.g. on Ctrl-C. It must be called before any git command runs.
+func SetContext(ctx context.Context) {
+	baseContext = ctx
+}
+
+// runContext runs args on r under ctx, through RunContext if r supports it.
+func runContext(ctx context.Context, r Runner, args ...string) (string, error) {
+	if cr, ok := r.(ContextRunner); ok {
+		return cr.RunContext(ctx, args...)
+	}
+	if err := ctx.Err(); err != nil {
+		return "", fmt.Errorf("command 'git %s' cancelled: %w", strings.Join(args, " "), err)
+	}
+	return r.Run(args...)
+}
+
+// SetRunner replaces DefaultRunner with r and returns a function that restores
+// the previous runner, e.g. 'defer gitops.SetRunner(fake)()' in a test (see
+// internal/gitopstest for a fake).
+func SetRunner(r Runner) (restore func()) {
+	previous := DefaultRunner
+	DefaultRunner = r
+	ResetCache() // cached answers came from the previous runner
+	return func() {
+		DefaultRunner = previous
+		ResetCache()
+	}
+}
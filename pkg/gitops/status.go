@@ -0,0 +1,251 @@
+package gitops
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrNoUpstream is returned by AheadBehind when the branch has no upstream configured.
+var ErrNoUpstream = errors.New("no upstream configured")
+
+// RepoStatus is the working tree and upstream state of one repository.
+type RepoStatus struct {
+	Path          string `json:"path"`
+	RelativePath  string `json:"relativePath"` // display path, filled in by the caller
+	Branch        string `json:"branch"`
+	Dirty         bool   `json:"dirty"`
+	Bare          bool   `json:"bare,omitempty"`        // no working tree: Dirty, State and upstream fields do not apply
+	BranchCount   int    `json:"branchCount,omitempty"` // bare repositories only: number of local branches
+	State         string `json:"state,omitempty"`       // StateMerging or StateRebasing while an operation is unfinished
+	OriginURL     string `json:"originUrl,omitempty"`   // only filled in when requested by the caller
+	NoOrigin      bool   `json:"noOrigin,omitempty"`    // origin was requested but the repository has no such remote
+	HasUpstream   bool   `json:"hasUpstream"`
+	Upstream      string `json:"upstream,omitempty"` // e.g. "origin/main"
+	Ahead         int    `json:"ahead"`
+	Behind        int    `json:"behind"`
+	Diverged      bool   `json:"diverged,omitempty"`      // both Ahead and Behind: usually a force-push or unpushed rebase
+	StatusError   string `json:"statusError,omitempty"`   // 'git status' failed; Dirty is then reported as true
+	UpstreamError string `json:"upstreamError,omitempty"` // ahead/behind could not be determined
+
+	// HEAD commit, only filled in when requested by the caller.
+	LastCommitRelative string `json:"lastCommitRelative,omitempty"` // e.g. "3 days ago"
+	LastCommitSubject  string `json:"lastCommitSubject,omitempty"`
+
+	// Number of stash entries, only filled in when requested by the caller.
+	StashCount int `json:"stashCount,omitempty"`
+
+	// Set by callers that count Ahead/Behind against another ref than @{u}.
+	CompareRef   string `json:"compareRef,omitempty"`   // e.g. "upstream/main"
+	CompareError string `json:"compareError,omitempty"` // why that ref could not be used; @{u} was used instead
+
+	// How the working tree was inspected, see StatusOptions.
+	DirtySkipped     bool `json:"dirtySkipped,omitempty"`     // not inspected at all: Dirty does not apply
+	UntrackedIgnored bool `json:"untrackedIgnored,omitempty"` // untracked files do not count as Dirty
+
+	// Pull request for Branch on the origin forge, only filled in when requested by the caller.
+	PullRequest      int    `json:"pullRequest,omitempty"`      // number of the open pull request
+	PullRequestState string `json:"pullRequestState,omitempty"` // PullRequestOpen, PullRequestNone or PullRequestUnknown
+}
+
+// AheadBehind returns how many commits HEAD is ahead of and behind rev in the
+// repository at repoPath. Use "@{u}" to compare against the upstream branch; if
+// it is not configured, ErrNoUpstream is returned.
+func AheadBehind(repoPath, rev string) (ahead, behind int, err error) {
+	if rev == "@{u}" {
+		if has, ok := cache.getHasUpstream(repoPath); ok && !has {
+			return 0, 0, ErrNoUpstream
+		}
+	}
+	revOutput, err := RunGitCommand("-C", repoPath, "rev-list", "--left-right", "--count", "HEAD..."+rev)
+	if err != nil {
+		if strings.Contains(err.Error(), "no upstream configured") || strings.Contains(err.Error(), "unknown revision") {
+			if rev == "@{u}" {
+				cache.setHasUpstream(repoPath, false)
+			}
+			return 0, 0, ErrNoUpstream
+		}
+		return 0, 0, err
+	}
+	if rev == "@{u}" {
+		cache.setHasUpstream(repoPath, true)
+	}
+	parts := strings.Split(strings.TrimSpace(revOutput), "\t")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rev-list output %q", revOutput)
+	}
+	ahead, errAhead := strconv.Atoi(parts[0])
+	behind, errBehind := strconv.Atoi(parts[1])
+	if errAhead != nil || errBehind != nil {
+		return 0, 0, fmt.Errorf("unexpected rev-list output %q", revOutput)
+	}
+	return ahead, behind, nil
+}
+
+// StatusOptions controls which parts of a repository GetRepoStatusWithOptions inspects.
+type StatusOptions struct {
+	// SkipWorkingTree leaves out the slow parts of 'git status' on large
+	// checkouts (the untracked file scan and submodules) and does not report
+	// Dirty, for callers that only need the branch and upstream state.
+	SkipWorkingTree bool
+	// IgnoreUntracked reports a repository whose only changes are untracked
+	// files (e.g. unignored build artifacts) as clean.
+	IgnoreUntracked bool
+}
+
+// GetRepoStatus collects the RepoStatus of the repository at repoPath. Failures
+// of individual git calls are recorded in the result rather than returned.
+func GetRepoStatus(repoPath string) RepoStatus {
+	return GetRepoStatusWithOptions(repoPath, StatusOptions{})
+}
+
+// GetRepoStatusWithOptions is GetRepoStatus with control over what is inspected.
+// The working tree, branch and upstream come from a single
+// 'git status --porcelain=v2 --branch' call (with SkipWorkingTree, one that
+// leaves out untracked files and submodules); if that call fails, the branch
+// and upstream are read separately.
+func GetRepoStatusWithOptions(repoPath string, opts StatusOptions) RepoStatus {
+	st := RepoStatus{Path: repoPath}
+
+	// --- Bare Repositories: only refs can be inspected ---
+	if IsBareRepo(repoPath) {
+		st.Bare = true
+		if branch, err := CurrentBranch(repoPath); err == nil {
+			st.Branch = branch
+		}
+		if branches, err := LocalBranches(repoPath); err == nil {
+			st.BranchCount = len(branches)
+		} else {
+			st.StatusError = err.Error()
+		}
+		return st
+	}
+
+	// --- Merge/Rebase In Progress ---
+	if state, err := InProgressOperation(repoPath); err == nil {
+		st.State = state
+	}
+
+	// --- Working Tree, Branch and Upstream in One Call ---
+	args := []string{"-C", repoPath, "status", "--porcelain=v2", "--branch"}
+	switch {
+	case opts.SkipWorkingTree:
+		args = append(args, "--untracked-files=no", "--ignore-submodules=all")
+		st.DirtySkipped = true
+	case opts.IgnoreUntracked:
+		args = append(args, "--untracked-files=no")
+		st.UntrackedIgnored = true
+	}
+	output, err := RunGitCommand(args...)
+	var ps PorcelainStatus
+	if err == nil {
+		ps, err = ParsePorcelainV2(output)
+	}
+	if err == nil {
+		if !opts.SkipWorkingTree {
+			st.Dirty = ps.Dirty()
+		}
+		st.Branch = ps.Branch
+		st.HasUpstream = ps.HasAheadBehind
+		if ps.HasAheadBehind {
+			st.Upstream = ps.Upstream
+		}
+		st.Ahead, st.Behind = ps.Ahead, ps.Behind
+		st.Diverged = ps.Ahead > 0 && ps.Behind > 0
+		cache.setHasUpstream(repoPath, ps.HasAheadBehind)
+		return st
+	}
+	// Fall back to separate calls for the branch and upstream below.
+	st.StatusError = err.Error()
+	if !opts.SkipWorkingTree {
+		st.Dirty = true
+	}
+
+	// --- Current Branch ---
+	if branch, err := CurrentBranch(repoPath); err == nil {
+		st.Branch = branch
+	}
+
+	// --- Check Ahead/Behind Status ---
+	ahead, behind, err := AheadBehind(repoPath, "@{u}")
+	switch {
+	case errors.Is(err, ErrNoUpstream):
+	case err != nil:
+		st.UpstreamError = err.Error()
+	default:
+		st.HasUpstream = true
+		st.Ahead, st.Behind = ahead, behind
+		st.Diverged = ahead > 0 && behind > 0
+		if upstream, err := RunGitCommand("-C", repoPath, "rev-parse", "--abbrev-ref", "@{u}"); err == nil {
+			st.Upstream = upstream
+		}
+	}
+	return st
+}
+
+// Values of RepoStatus.PullRequestState.
+const (
+	PullRequestOpen    = "open"
+	PullRequestNone    = "none"
+	PullRequestUnknown = "unknown" // unsupported forge, or the lookup failed
+)
+
+// Values of RepoStatus.State for an operation left in progress.
+const (
+	StateMerging  = "merging"
+	StateRebasing = "rebasing"
+)
+
+// GitDir returns the path of the repository's git directory: repoPath/.git, the
+// directory a '.git' file points to (as used by submodules and worktrees), or
+// repoPath itself for a bare repository.
+func GitDir(repoPath string) (string, error) {
+	dotGit := filepath.Join(repoPath, ".git")
+	info, err := os.Stat(dotGit)
+	if err != nil {
+		if IsBareRepo(repoPath) {
+			return repoPath, nil
+		}
+		return "", err
+	}
+	if info.IsDir() {
+		return dotGit, nil
+	}
+	data, err := os.ReadFile(dotGit)
+	if err != nil {
+		return "", err
+	}
+	gitDir, found := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !found {
+		return "", fmt.Errorf("%s is not a valid gitfile", dotGit)
+	}
+	gitDir = strings.TrimSpace(gitDir)
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(repoPath, gitDir)
+	}
+	return gitDir, nil
+}
+
+// InProgressOperation reports whether a merge or rebase was started but not
+// finished in the repository at repoPath. It returns StateMerging, StateRebasing
+// or "" if neither is in progress.
+func InProgressOperation(repoPath string) (string, error) {
+	gitDir, err := GitDir(repoPath)
+	if err != nil {
+		return "", err
+	}
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(gitDir, name))
+		return err == nil
+	}
+	switch {
+	case exists("rebase-merge"), exists("rebase-apply"):
+		return StateRebasing, nil
+	case exists("MERGE_HEAD"):
+		return StateMerging, nil
+	}
+	return "", nil
+}
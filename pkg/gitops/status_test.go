@@ -0,0 +1,150 @@
+package gitops
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/OmSingh2003/git-util/internal/gitopstest"
+)
+
+// noUpstreamErr is how RunGitCommand reports a branch without an upstream.
+var noUpstreamErr = errors.New("command 'git rev-list' failed: exit status 128\nStderr: fatal: no upstream configured for branch 'topic'")
+
+// errAny marks test cases that expect some error other than ErrNoUpstream.
+var errAny = errors.New("any error")
+
+func TestAheadBehind(t *testing.T) {
+	tests := []struct {
+		name                  string
+		output                string
+		err                   error
+		wantAhead, wantBehind int
+		wantErr               error // nil, ErrNoUpstream, or errAny for any other error
+	}{
+		{name: "up to date", output: "0\t0"},
+		{name: "ahead", output: "3\t0", wantAhead: 3},
+		{name: "behind", output: "0\t5", wantBehind: 5},
+		{name: "diverged", output: "2\t7", wantAhead: 2, wantBehind: 7},
+		{name: "no upstream", err: noUpstreamErr, wantErr: ErrNoUpstream},
+		{name: "unexpected output", output: "garbage", wantErr: errAny},
+		{name: "git failure", err: errors.New("fatal: not a git repository"), wantErr: errAny},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := gitopstest.NewFakeRunner().
+				On(tt.output, tt.err, "-C", "/repo", "rev-list", "--left-right", "--count", "HEAD...@{u}")
+			defer SetRunner(fake)()
+
+			ahead, behind, err := AheadBehind("/repo", "@{u}")
+			var ok bool
+			switch tt.wantErr {
+			case nil:
+				ok = err == nil
+			case errAny:
+				ok = err != nil && !errors.Is(err, ErrNoUpstream)
+			default:
+				ok = errors.Is(err, tt.wantErr)
+			}
+			if !ok {
+				t.Fatalf("AheadBehind() error = %v, want %v", err, tt.wantErr)
+			}
+			if ahead != tt.wantAhead || behind != tt.wantBehind {
+				t.Errorf("AheadBehind() = %d, %d, want %d, %d", ahead, behind, tt.wantAhead, tt.wantBehind)
+			}
+		})
+	}
+}
+
+func TestAheadBehindCachesMissingUpstream(t *testing.T) {
+	fake := gitopstest.NewFakeRunner().
+		On("", noUpstreamErr, "-C", "/repo", "rev-list", "--left-right", "--count", "HEAD...@{u}")
+	defer SetRunner(fake)()
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := AheadBehind("/repo", "@{u}"); !errors.Is(err, ErrNoUpstream) {
+			t.Fatalf("AheadBehind() error = %v, want ErrNoUpstream", err)
+		}
+	}
+	if has, err := HasUpstream("/repo"); err != nil || has {
+		t.Errorf("HasUpstream() = %v, %v, want false, nil", has, err)
+	}
+	if n := len(fake.Calls()); n != 1 {
+		t.Errorf("git was run %d times, want 1 (the answer is cached)", n)
+	}
+}
+
+func TestGetRepoStatusWithOptions(t *testing.T) {
+	repo := t.TempDir() // not a repository on disk: every git call goes to the fake
+	statusArgs := []string{"-C", repo, "status", "--porcelain=v2", "--branch"}
+	with := func(extra ...string) []string { return append(append([]string(nil), statusArgs...), extra...) }
+
+	type call struct {
+		output string
+		err    error
+		args   []string
+	}
+	tests := []struct {
+		name  string
+		opts  StatusOptions
+		calls []call
+		want  RepoStatus
+	}{
+		{
+			name: "clean and up to date",
+			calls: []call{{output: "# branch.oid 1a2b\n# branch.head main\n# branch.upstream origin/main\n# branch.ab +0 -0",
+				args: statusArgs}},
+			want: RepoStatus{Branch: "main", HasUpstream: true, Upstream: "origin/main"},
+		},
+		{
+			name: "dirty and diverged",
+			calls: []call{{output: "# branch.head main\n# branch.upstream origin/main\n# branch.ab +1 -2\n? new.txt",
+				args: statusArgs}},
+			want: RepoStatus{Branch: "main", Dirty: true, HasUpstream: true, Upstream: "origin/main",
+				Ahead: 1, Behind: 2, Diverged: true},
+		},
+		{
+			name:  "no upstream",
+			calls: []call{{output: "# branch.head topic\n1 .M N... 100644 100644 100644 1a2b 1a2b f.go", args: statusArgs}},
+			want:  RepoStatus{Branch: "topic", Dirty: true},
+		},
+		{
+			name:  "ignore untracked",
+			opts:  StatusOptions{IgnoreUntracked: true},
+			calls: []call{{output: "# branch.head main", args: with("--untracked-files=no")}},
+			want:  RepoStatus{Branch: "main", UntrackedIgnored: true},
+		},
+		{
+			name: "skip working tree",
+			opts: StatusOptions{SkipWorkingTree: true},
+			calls: []call{{output: "# branch.head main\n# branch.upstream origin/main\n# branch.ab +0 -4\n1 .M N... 100644 100644 100644 1a2b 1a2b f.go",
+				args: with("--untracked-files=no", "--ignore-submodules=all")}},
+			want: RepoStatus{Branch: "main", DirtySkipped: true, HasUpstream: true, Upstream: "origin/main", Behind: 4},
+		},
+		{
+			name: "status fails: branch and upstream read separately",
+			calls: []call{
+				{err: errors.New("status failed"), args: statusArgs},
+				{output: "main", args: []string{"-C", repo, "rev-parse", "--abbrev-ref", "HEAD"}},
+				{output: "1\t0", args: []string{"-C", repo, "rev-list", "--left-right", "--count", "HEAD...@{u}"}},
+				{output: "origin/main", args: []string{"-C", repo, "rev-parse", "--abbrev-ref", "@{u}"}},
+			},
+			want: RepoStatus{Branch: "main", Dirty: true, StatusError: "status failed",
+				HasUpstream: true, Upstream: "origin/main", Ahead: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := gitopstest.NewFakeRunner()
+			for _, c := range tt.calls {
+				fake.On(c.output, c.err, c.args...)
+			}
+			defer SetRunner(fake)()
+
+			got := GetRepoStatusWithOptions(repo, tt.opts)
+			tt.want.Path = repo
+			if got != tt.want {
+				t.Errorf("GetRepoStatusWithOptions() =\n  %+v\nwant\n  %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package gitops
+
+import "strings"
+
+// LatestTag returns the most recent tag reachable from HEAD in the repository
+// at repoPath ('git describe --tags --abbrev=0') and the number of commits on
+// HEAD since it. tag is empty if no tag is reachable, including in a
+// repository without commits.
+func LatestTag(repoPath string) (tag string, commitsSince int, err error) {
+	tag, err = RunGitCommand("-C", repoPath, "describe", "--tags", "--abbrev=0")
+	if err != nil {
+		msg := err.Error()
+		if strings.Contains(msg, "No names found") || strings.Contains(msg, "No tags can describe") ||
+			strings.Contains(msg, "Not a valid object name") {
+			return "", 0, nil
+		}
+		return "", 0, err
+	}
+	commitsSince, err = CountCommits(repoPath, tag, "HEAD")
+	return tag, commitsSince, err
+}
+
+// TagAge returns the relative date of the commit tag points at, e.g. "3 weeks ago".
+func TagAge(repoPath, tag string) (string, error) {
+	return RunGitCommand("-C", repoPath, "log", "-1", "--format=%cr", "refs/tags/"+tag, "--")
+}
@@ -0,0 +1,85 @@
+package gitops
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Worktree is one entry of 'git worktree list --porcelain'.
+type Worktree struct {
+	Path   string
+	Branch string // short branch name; empty when detached or bare
+	// Prunable is git's reason for considering the worktree stale, e.g.
+	// "gitdir file points to non-existent location", or empty.
+	Prunable string
+}
+
+// ListWorktrees returns the worktrees of the repository at repoPath, the main
+// worktree first.
+func ListWorktrees(repoPath string) ([]Worktree, error) {
+	output, err := RunGitCommand("-C", repoPath, "worktree", "list", "--porcelain")
+	if err != nil {
+		return nil, err
+	}
+	var worktrees []Worktree
+	for _, block := range strings.Split(output, "\n\n") {
+		var wt Worktree
+		for _, line := range strings.Split(block, "\n") {
+			key, value, _ := strings.Cut(line, " ")
+			switch key {
+			case "worktree":
+				wt.Path = value
+			case "branch":
+				wt.Branch = strings.TrimPrefix(value, "refs/heads/")
+			case "prunable":
+				wt.Prunable = value
+				if wt.Prunable == "" {
+					wt.Prunable = "stale"
+				}
+			}
+		}
+		if wt.Path != "" {
+			worktrees = append(worktrees, wt)
+		}
+	}
+	return worktrees, nil
+}
+
+// CommonGitDir returns the absolute path of the git directory shared by all
+// worktrees of the repository at repoPath.
+func CommonGitDir(repoPath string) (string, error) {
+	dir, err := RunGitCommand("-C", repoPath, "rev-parse", "--git-common-dir")
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(repoPath, dir)
+	}
+	return filepath.Clean(dir), nil
+}
+
+// PruneWorktrees runs 'git worktree prune' in the repository at repoPath and
+// returns how many worktree administrative directories (in
+// <common dir>/worktrees) it removed.
+func PruneWorktrees(repoPath string) (int, error) {
+	commonDir, err := CommonGitDir(repoPath)
+	if err != nil {
+		return 0, err
+	}
+	adminDir := filepath.Join(commonDir, "worktrees")
+	before := countDirEntries(adminDir)
+	if _, err := RunGitCommand("-C", repoPath, "worktree", "prune"); err != nil {
+		return 0, err
+	}
+	return before - countDirEntries(adminDir), nil
+}
+
+// countDirEntries returns the number of entries in dir, 0 if it cannot be read.
+func countDirEntries(dir string) int {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0
+	}
+	return len(entries)
+}